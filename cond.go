@@ -1,17 +1,22 @@
 package sqlb
 
-func joinSqler(sep string, v []Sqler) SqlerFunc {
+import (
+	"errors"
+	"fmt"
+)
+
+func joinSqler(sep string, v []Sqler) sqlerFunc {
 	return func(w Writer) (err error) {
 		lv := len(v)
 		if lv == 0 {
 			return nil
 		}
-		if err = v[0].Sql(w); err != nil {
+		if err = writeSqler(w, v[0]); err != nil {
 			return err
 		}
 		for i := 1; i < lv; i++ {
 			w.WriteString(sep)
-			if err = v[i].Sql(w); err != nil {
+			if err = writeSqler(w, v[i]); err != nil {
 				return err
 			}
 		}
@@ -19,25 +24,324 @@ func joinSqler(sep string, v []Sqler) SqlerFunc {
 	}
 }
 
+// ErrEmptyCond は条件式がないときのエラーです.
+//
+// AndElse, OrElse で empty に nil を指定したときに返します.
+var ErrEmptyCond = errors.New("empty condition")
+
+// cond は条件式を AND または OR で繋げた Sqler です.
+type cond struct {
+	op    string
+	v     []Sqler
+	empty Sqler
+
+	// implicit は And, Or が作成した既定の empty を持つことを表します.
+	implicit bool
+}
+
 // And は条件式を表す Sqler を AND で繋げます.
+//
+// AND と OR を組み合わせるときは内側の And, Or を自動的に括弧で括ります.
+// T などで記述した条件式は括弧で括らないので, 必要に応じて Bracket を使ってください.
+//
+// 条件式がないときは TRUE になります.
 func And(v ...Sqler) Sqler {
-	return joinSqler(" AND ", v)
+	return &cond{" AND ", v, boolSqler(true), true}
 }
 
 // Or は条件式を表す Sqler を OR で繋げます.
+//
+// 条件式がないときは FALSE になります.
 func Or(v ...Sqler) Sqler {
-	return joinSqler(" OR ", v)
+	return &cond{" OR ", v, boolSqler(false), true}
+}
+
+// AndElse は And と同じですが, 条件式がないときは empty を展開します.
+//
+// empty が nil のときは ErrEmptyCond を返します.
+func AndElse(empty Sqler, v ...Sqler) Sqler {
+	return &cond{" AND ", v, empty, false}
+}
+
+// OrElse は Or と同じですが, 条件式がないときは empty を展開します.
+//
+// empty が nil のときは ErrEmptyCond を返します.
+func OrElse(empty Sqler, v ...Sqler) Sqler {
+	return &cond{" OR ", v, empty, false}
+}
+
+// alwaysTrue は sqler が条件式のない And のように常に TRUE になるかを返します.
+func alwaysTrue(sqler Sqler) bool {
+	c, ok := sqler.(*cond)
+	if !ok || !c.implicit {
+		return false
+	}
+	if c.op == " AND " {
+		for _, v := range c.v {
+			if !alwaysTrue(v) {
+				return false
+			}
+		}
+		return true
+	}
+	for _, v := range c.v {
+		if alwaysTrue(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// Sql は条件式を展開します.
+func (c *cond) Sql(w Writer) error {
+	if len(c.v) == 0 {
+		if c.empty == nil {
+			return ErrEmptyCond
+		}
+		return writeSqler(w, c.empty)
+	}
+	for i, sqler := range c.v {
+		if i > 0 {
+			w.WriteString(c.op)
+		}
+		if x, ok := sqler.(*cond); ok && x.op != c.op && len(x.v) > 1 {
+			sqler = Bracket(x)
+		}
+		if err := writeSqler(w, sqler); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// boolSqler は TRUE, FALSE を展開します.
+func boolSqler(v bool) Sqler {
+	fn := func(w Writer) error {
+		return dialect().WriteBool(w, v)
+	}
+	return sqlerFunc(fn)
+}
+
+// Not は条件式を否定します.
+func Not(sqler Sqler) Sqler {
+	fn := func(w Writer) error {
+		w.WriteString("NOT ")
+		return Bracket(sqler).Sql(w)
+	}
+	return sqlerFunc(fn)
+}
+
+// compare は `column` op value を表す Sqler を作成します.
+//
+// v が Sqler のときは値の代わりに展開します. v にリストは指定できません.
+func compare(column, op string, v ...any) Sqler {
+	fn := func(w Writer) error {
+		if err := writeIdent(w, column); err != nil {
+			return fmt.Errorf("ident: %v: %w", column, err)
+		}
+		w.WriteString(op)
+		for i, val := range v {
+			if i > 0 {
+				w.WriteString(" AND ")
+			}
+			if err := putOperand(w, val); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return sqlerFunc(fn)
+}
+
+// Eq は `column` = v を表す Sqler を作成します.
+//
+// v が nil のときは `column` IS NULL を表します.
+func Eq(column string, v any) Sqler {
+	if isNull(v) {
+		return IsNull(column)
+	}
+	return compare(column, " = ", v)
+}
+
+// Ne は `column` != v を表す Sqler を作成します.
+//
+// v が nil のときは `column` IS NOT NULL を表します.
+func Ne(column string, v any) Sqler {
+	if isNull(v) {
+		return IsNotNull(column)
+	}
+	return compare(column, " != ", v)
+}
+
+// Lt は `column` < v を表す Sqler を作成します.
+func Lt(column string, v any) Sqler {
+	return compare(column, " < ", v)
+}
+
+// Le は `column` <= v を表す Sqler を作成します.
+func Le(column string, v any) Sqler {
+	return compare(column, " <= ", v)
+}
+
+// Gt は `column` > v を表す Sqler を作成します.
+func Gt(column string, v any) Sqler {
+	return compare(column, " > ", v)
+}
+
+// Ge は `column` >= v を表す Sqler を作成します.
+func Ge(column string, v any) Sqler {
+	return compare(column, " >= ", v)
+}
+
+// Between は `column` BETWEEN lo AND hi を表す Sqler を作成します.
+func Between(column string, lo, hi any) Sqler {
+	return compare(column, " BETWEEN ", lo, hi)
+}
+
+// Like は `column` LIKE pattern を表す Sqler を作成します.
+func Like(column string, pattern any) Sqler {
+	return compare(column, " LIKE ", pattern)
+}
+
+// In は `column` IN (v...) を表す Sqler を作成します.
+func In(column string, v ...any) Sqler {
+	fn := func(w Writer) error {
+		if err := writeIdent(w, column); err != nil {
+			return fmt.Errorf("ident: %v: %w", column, err)
+		}
+		w.WriteByte(' ')
+		return putIn(w, "==", v)
+	}
+	return sqlerFunc(fn)
+}
+
+// IsNull は `column` IS NULL を表す Sqler を作成します.
+func IsNull(column string) Sqler {
+	return isNullCond(column, "==")
+}
+
+// IsNotNull は `column` IS NOT NULL を表す Sqler を作成します.
+func IsNotNull(column string) Sqler {
+	return isNullCond(column, "!=")
+}
+
+func isNullCond(column, eq string) Sqler {
+	fn := func(w Writer) error {
+		if err := writeIdent(w, column); err != nil {
+			return fmt.Errorf("ident: %v: %w", column, err)
+		}
+		w.WriteByte(' ')
+		return putIsNull(w, eq)
+	}
+	return sqlerFunc(fn)
 }
 
 // Bracket は条件式を表す Sqler を括弧で括ります.
 func Bracket(sqler Sqler) Sqler {
 	fn := func(w Writer) error {
 		w.WriteByte('(')
-		if err := sqler.Sql(w); err != nil {
+		if err := writeSqler(w, sqler); err != nil {
 			return err
 		}
 		w.WriteByte(')')
 		return nil
 	}
-	return SqlerFunc(fn)
+	return sqlerFunc(fn)
+}
+
+// Compound は UNION などの集合演算で SELECT 文を組み合わせる Sqler です.
+//
+// ORDER BY, LIMIT 句を持つ SelectBuilder や異なる集合演算の Compound は
+// 自動的に括弧で括ります.
+// Compound は SelectBuilder と同様に不変です.
+//
+//	q := sqlb.UnionAll(parts...).OrderBy("id").Limit(100)
+type Compound struct {
+	op      string
+	v       []Sqler
+	orderBy []any
+	limit   int64
+	offset  int64
+}
+
+func newCompound(op string, v []Sqler) *Compound {
+	return &Compound{op: op, v: v, limit: -1, offset: -1}
+}
+
+// Union は SELECT 文を UNION で繋げます.
+func Union(v ...Sqler) *Compound {
+	return newCompound(" UNION ", v)
+}
+
+// UnionAll は SELECT 文を UNION ALL で繋げます.
+func UnionAll(v ...Sqler) *Compound {
+	return newCompound(" UNION ALL ", v)
+}
+
+// Intersect は SELECT 文を INTERSECT で繋げます.
+func Intersect(v ...Sqler) *Compound {
+	return newCompound(" INTERSECT ", v)
+}
+
+// Except は SELECT 文を EXCEPT で繋げます.
+func Except(v ...Sqler) *Compound {
+	return newCompound(" EXCEPT ", v)
+}
+
+// OrderBy は集合演算の結果全体に対する ORDER BY 句のカラムを追加します.
+func (c *Compound) OrderBy(columns ...any) *Compound {
+	x := *c
+	x.orderBy = appendClone(x.orderBy, columns...)
+	return &x
+}
+
+// Limit は集合演算の結果全体から取得する最大行数を指定します.
+func (c *Compound) Limit(n int64) *Compound {
+	x := *c
+	x.limit = n
+	return &x
+}
+
+// Offset は集合演算の結果全体で読み飛ばす行数を指定します.
+func (c *Compound) Offset(n int64) *Compound {
+	x := *c
+	x.offset = n
+	return &x
+}
+
+// hasTail は ORDER BY, LIMIT, OFFSET 句を持つか調べます.
+func (c *Compound) hasTail() bool {
+	return len(c.orderBy) > 0 || c.limit >= 0 || c.offset >= 0
+}
+
+// needBracket は sqler を c の i 番目の被演算子とするとき括弧が必要か調べます.
+//
+// EXCEPT は結合則を満たさないので 2 番目以降の集合演算は常に括弧で囲みます.
+func (c *Compound) needBracket(i int, sqler Sqler) bool {
+	switch x := sqler.(type) {
+	case *SelectBuilder:
+		return len(x.orderBy) > 0 || x.limit >= 0 || x.offset >= 0 || x.forUpdate
+	case *Compound:
+		return x.op != c.op || x.hasTail() || i > 0 && c.op == " EXCEPT "
+	}
+	return false
+}
+
+// Sql は集合演算を展開します.
+func (c *Compound) Sql(w Writer) error {
+	if len(c.v) == 0 {
+		return fmt.Errorf("compound: %w", errEmptySlice)
+	}
+	for i, sqler := range c.v {
+		if i > 0 {
+			w.WriteString(c.op)
+		}
+		if c.needBracket(i, sqler) {
+			sqler = Bracket(sqler)
+		}
+		if err := writeSqler(w, sqler); err != nil {
+			return err
+		}
+	}
+	return putOrderLimit(w, c.orderBy, c.limit, c.offset)
 }
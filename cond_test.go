@@ -2,6 +2,8 @@ package sqlb
 
 import (
 	"bytes"
+	"database/sql"
+	"fmt"
 	"testing"
 )
 
@@ -28,3 +30,136 @@ func TestCond(t *testing.T) {
 		t.Errorf("%s = %q, want %q", "test Cond #2", got, want)
 	}
 }
+
+func TestCondConstructors(t *testing.T) {
+	tests := []struct {
+		sqler Sqler
+		want  string
+		err   string
+	}{
+		{Eq("age", 20), "`age` = 20", ""},
+		{Ne("name", "a"), "`name` != 'a'", ""},
+		{Eq("age", nil), "`age` IS NULL", ""},
+		{Ne("age", nil), "`age` IS NOT NULL", ""},
+		{Eq("token", Sensitive(nil)), "`token` IS NULL", ""},
+		{Eq("nick", sql.NullString{}), "`nick` IS NULL", ""},
+		{Ne("nick", Sensitive(sql.NullString{})), "`nick` IS NOT NULL", ""},
+		{Eq("nick", sql.NullString{String: "a", Valid: true}), "`nick` = 'a'", ""},
+		{IsNotNull("age"), "`age` IS NOT NULL", ""},
+		{Lt("age", 20), "`age` < 20", ""},
+		{Le("age", 20), "`age` <= 20", ""},
+		{Gt("age", 20), "`age` > 20", ""},
+		{Ge("age", 20), "`age` >= 20", ""},
+		{Eq("p.id", T("a.person_id")), "`p`.`id` = a.person_id", ""},
+		{Between("age", 20, 30), "`age` BETWEEN 20 AND 30", ""},
+		{Like("name", "A%"), "`name` LIKE 'A%'", ""},
+		{In("id", 1, 2, 3), "`id` IN (1, 2, 3)", ""},
+		{IsNull("nickname"), "`nickname` IS NULL", ""},
+		{Not(IsNull("nickname")), "NOT (`nickname` IS NULL)", ""},
+		{Not(Or(Eq("a", 1), Eq("b", 2))), "NOT (`a` = 1 OR `b` = 2)", ""},
+		{In("id"), "", "empty array or slice"},
+		{Eq("*", 1), "", "ident: *: not allowed asterisk"},
+		{Lt("a", []any{1, 2}), "", "value: got type []interface {}: no value type"},
+		{Eq("a", [][]any{{1}}), "", "value: got type [][]interface {}: no value type"},
+		{Eq("a", []Kv{{"x", 1}}), "", "value: got type []sqlb.Kv: no value type"},
+		{Between("a", 1, []any{2}), "", "value: got type []interface {}: no value type"},
+	}
+
+	for i, te := range tests {
+		var goterr string
+
+		name := fmt.Sprintf("test Cond constructors #%d", i)
+		got, err := Stringify(te.sqler)
+		if err != nil {
+			goterr = err.Error()
+		}
+		if goterr != te.err {
+			t.Errorf("%s errored %q, want %q", name, goterr, te.err)
+		}
+		if got != te.want {
+			t.Errorf("%s returned %q, want %q", name, got, te.want)
+		}
+	}
+}
+
+func TestCondNesting(t *testing.T) {
+	a, b, c := StringSqler("a"), StringSqler("b"), StringSqler("c")
+
+	tests := []struct {
+		sqler Sqler
+		want  string
+		err   string
+	}{
+		{And(Or(a, b), c), "(a OR b) AND c", ""},
+		{Or(And(a, b), c), "(a AND b) OR c", ""},
+		{And(And(a, b), c), "a AND b AND c", ""},
+		{And(Or(a), c), "a AND c", ""},
+		{And(), "TRUE", ""},
+		{Or(), "FALSE", ""},
+		{And(a, Or()), "a AND FALSE", ""},
+		{AndElse(StringSqler("1 = 1")), "1 = 1", ""},
+		{OrElse(nil), "", "empty condition"},
+		{AndElse(nil, a, b), "a AND b", ""},
+	}
+
+	for i, te := range tests {
+		var goterr string
+
+		name := fmt.Sprintf("test Cond nesting #%d", i)
+		got, err := Stringify(te.sqler)
+		if err != nil {
+			goterr = err.Error()
+		}
+		if goterr != te.err {
+			t.Errorf("%s errored %q, want %q", name, goterr, te.err)
+		}
+		if got != te.want {
+			t.Errorf("%s returned %q, want %q", name, got, te.want)
+		}
+	}
+}
+
+func TestCompound(t *testing.T) {
+	a := Select("id").From("a")
+	b := Select("id").From("b")
+	c := Select("id").From("c").OrderBy("id").Limit(5)
+
+	tests := []struct {
+		sqler Sqler
+		want  string
+		err   string
+	}{
+		{Union(a, b), "SELECT `id` FROM `a` UNION SELECT `id` FROM `b`", ""},
+		{UnionAll(a, b).OrderBy("id").Limit(10).Offset(20),
+			"SELECT `id` FROM `a` UNION ALL SELECT `id` FROM `b` ORDER BY `id` LIMIT 10 OFFSET 20", ""},
+		{Union(a, c), "SELECT `id` FROM `a` UNION (SELECT `id` FROM `c` ORDER BY `id` LIMIT 5)", ""},
+		{Union(Union(a, b), T("SELECT 1")), "SELECT `id` FROM `a` UNION SELECT `id` FROM `b` UNION SELECT 1", ""},
+		{Except(UnionAll(a, b), Intersect(a, b)),
+			"(SELECT `id` FROM `a` UNION ALL SELECT `id` FROM `b`) EXCEPT (SELECT `id` FROM `a` INTERSECT SELECT `id` FROM `b`)", ""},
+		{Union(a, Union(a, b).Limit(1)),
+			"SELECT `id` FROM `a` UNION (SELECT `id` FROM `a` UNION SELECT `id` FROM `b` LIMIT 1)", ""},
+		{Except(a, Except(b, c)),
+			"SELECT `id` FROM `a` EXCEPT (SELECT `id` FROM `b` EXCEPT (SELECT `id` FROM `c` ORDER BY `id` LIMIT 5))", ""},
+		{Except(Except(a, b), a),
+			"SELECT `id` FROM `a` EXCEPT SELECT `id` FROM `b` EXCEPT SELECT `id` FROM `a`", ""},
+		{Intersect(a, Union(a, b)),
+			"SELECT `id` FROM `a` INTERSECT (SELECT `id` FROM `a` UNION SELECT `id` FROM `b`)", ""},
+		{Union(), "", "compound: empty array or slice"},
+	}
+
+	for i, te := range tests {
+		var goterr string
+
+		name := fmt.Sprintf("test Compound #%d", i)
+		got, err := Stringify(te.sqler)
+		if err != nil {
+			goterr = err.Error()
+		}
+		if goterr != te.err {
+			t.Errorf("%s errored %q, want %q", name, goterr, te.err)
+		}
+		if got != te.want {
+			t.Errorf("%s returned %q, want %q", name, got, te.want)
+		}
+	}
+}
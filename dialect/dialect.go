@@ -14,6 +14,38 @@ var (
 	ErrIdentQuote   = errors.New("not allowed ident quote or brackets")
 )
 
+// UpsertStyle は UPSERT の構文を表します.
+type UpsertStyle int
+
+const (
+	// UpsertOnDuplicateKey は ON DUPLICATE KEY UPDATE col = VALUES(col) です.
+	UpsertOnDuplicateKey UpsertStyle = iota
+
+	// UpsertOnDuplicateKeyAlias は MySQL 8.0.20 以降の行エイリアスを使う
+	// AS new ON DUPLICATE KEY UPDATE col = new.col です.
+	UpsertOnDuplicateKeyAlias
+
+	// UpsertOnConflict は ON CONFLICT (...) DO UPDATE SET col = EXCLUDED.col です.
+	UpsertOnConflict
+
+	// UpsertMerge は MERGE INTO ... USING ... です.
+	UpsertMerge
+)
+
+// ReturningStyle は INSERT, UPDATE, DELETE で変更した行を返す構文を表します.
+type ReturningStyle int
+
+const (
+	// ReturningNone は変更した行を返す構文がないことを表します.
+	ReturningNone ReturningStyle = iota
+
+	// ReturningClause は末尾に付ける RETURNING col です.
+	ReturningClause
+
+	// ReturningOutput は OUTPUT INSERTED.col, OUTPUT DELETED.col です.
+	ReturningOutput
+)
+
 type Writer interface {
 	io.Writer
 	io.ByteWriter
@@ -29,6 +61,51 @@ type Dialect interface {
 	WriteString(w Writer, v string) error
 	WriteBytes(w Writer, v []byte) error
 	WriteTime(w Writer, v time.Time) error
+
+	// InsertIgnore は重複するキーを無視する INSERT の動詞と末尾に付ける句を返します.
+	InsertIgnore() (verb, suffix string)
+
+	// UpsertStyle は UPSERT の構文を返します.
+	UpsertStyle() UpsertStyle
+
+	// ReturningStyle は変更した行を返す構文を返します.
+	ReturningStyle() ReturningStyle
+
+	// WithRecursive は再帰的な CTE で WITH に続けるキーワードを返します.
+	// キーワードが不要なときは空文字列を返します.
+	WithRecursive() string
+
+	// Materialized は CTE の MATERIALIZED ヒントをサポートするか返します.
+	Materialized() bool
+
+	// LikeEscape は LIKE のパターンで使うエスケープ文字を返します.
+	LikeEscape() byte
+
+	// RowValues は (a, b) > (1, 2) のような行値の比較をサポートするか返します.
+	RowValues() bool
+
+	// NullsOrdering は ORDER BY の NULLS FIRST, NULLS LAST をサポートするか返します.
+	NullsOrdering() bool
+
+	// WriteLimit は取得する最大行数 limit と読み飛ばす行数 offset の句を w に書き込みます.
+	// limit, offset が負のときはその値を指定しません. 少なくとも一方は 0 以上です.
+	WriteLimit(w Writer, limit, offset int64) error
+
+	// WriteForUpdate は SELECT で行をロックする句を w に書き込みます.
+	WriteForUpdate(w Writer) error
+
+	// WriteSavepoint はセーブポイントを作成する SQL を w に書き込みます.
+	WriteSavepoint(w Writer, name string) error
+
+	// WriteRollbackTo はセーブポイントまでロールバックする SQL を w に書き込みます.
+	WriteRollbackTo(w Writer, name string) error
+
+	// WriteReleaseSavepoint はセーブポイントを解放する SQL を w に書き込みます.
+	// 解放する必要がないときは何も書き込みません.
+	WriteReleaseSavepoint(w Writer, name string) error
+
+	// IsRetryable はデッドロックなどトランザクションを再試行すれば成功しうるエラーか判定します.
+	IsRetryable(err error) bool
 }
 
 var d Dialect
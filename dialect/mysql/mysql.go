@@ -2,10 +2,13 @@ package mysql
 
 import (
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	d "github.com/17e10/go-sqlb/dialect"
+	driver "github.com/go-sql-driver/mysql"
 )
 
 type Writer = d.Writer
@@ -14,7 +17,16 @@ func init() {
 	d.SetDialect(mysql{})
 }
 
-type mysql struct{}
+type mysql struct {
+	rowAlias bool
+}
+
+// SetRowAlias は UPSERT で MySQL 8.0.20 以降の行エイリアスを使うか設定します.
+//
+// 既定では VALUES(col) を使います.
+func SetRowAlias(enable bool) {
+	d.SetDialect(mysql{rowAlias: enable})
+}
 
 // WriteIdent は識別子の SQL 文字列を w に書き込みます.
 func (mysql) WriteIdent(w Writer, s string) error {
@@ -128,3 +140,105 @@ func (mysql) WriteTime(w Writer, tm time.Time) error {
 	w.WriteString(tm.Format("'2006-01-02 15:04:05.999999'"))
 	return nil
 }
+
+// InsertIgnore は INSERT IGNORE を返します.
+func (mysql) InsertIgnore() (verb, suffix string) {
+	return "INSERT IGNORE", ""
+}
+
+// UpsertStyle は ON DUPLICATE KEY UPDATE を返します.
+func (m mysql) UpsertStyle() d.UpsertStyle {
+	if m.rowAlias {
+		return d.UpsertOnDuplicateKeyAlias
+	}
+	return d.UpsertOnDuplicateKey
+}
+
+// ReturningStyle は ReturningNone を返します.
+//
+// MySQL には変更した行を返す構文がありません.
+func (mysql) ReturningStyle() d.ReturningStyle {
+	return d.ReturningNone
+}
+
+// WithRecursive は RECURSIVE を返します.
+func (mysql) WithRecursive() string {
+	return "RECURSIVE"
+}
+
+// Materialized は false を返します.
+//
+// MySQL は CTE の MATERIALIZED ヒントをサポートしません.
+func (mysql) Materialized() bool {
+	return false
+}
+
+// LikeEscape はバックスラッシュを返します.
+func (mysql) LikeEscape() byte {
+	return '\\'
+}
+
+// RowValues は true を返します.
+func (mysql) RowValues() bool {
+	return true
+}
+
+// NullsOrdering は false を返します.
+//
+// MySQL は NULLS FIRST, NULLS LAST をサポートしません.
+func (mysql) NullsOrdering() bool {
+	return false
+}
+
+// maxLimit は OFFSET だけを指定するときに LIMIT に書き込む値です.
+const maxLimit = "18446744073709551615"
+
+// WriteLimit は LIMIT n OFFSET m を w に書き込みます.
+//
+// MySQL は LIMIT のない OFFSET をサポートしないので, offset だけのときは LIMIT に最大値を書き込みます.
+func (mysql) WriteLimit(w Writer, limit, offset int64) error {
+	w.WriteString("LIMIT ")
+	if limit >= 0 {
+		w.WriteString(strconv.FormatInt(limit, 10))
+	} else {
+		w.WriteString(maxLimit)
+	}
+	if offset >= 0 {
+		w.WriteString(" OFFSET ")
+		w.WriteString(strconv.FormatInt(offset, 10))
+	}
+	return nil
+}
+
+// WriteForUpdate は FOR UPDATE を w に書き込みます.
+func (mysql) WriteForUpdate(w Writer) error {
+	w.WriteString("FOR UPDATE")
+	return nil
+}
+
+// WriteSavepoint は SAVEPOINT name を w に書き込みます.
+func (m mysql) WriteSavepoint(w Writer, name string) error {
+	w.WriteString("SAVEPOINT ")
+	return m.WriteIdent(w, name)
+}
+
+// WriteRollbackTo は ROLLBACK TO SAVEPOINT name を w に書き込みます.
+func (m mysql) WriteRollbackTo(w Writer, name string) error {
+	w.WriteString("ROLLBACK TO SAVEPOINT ")
+	return m.WriteIdent(w, name)
+}
+
+// WriteReleaseSavepoint は RELEASE SAVEPOINT name を w に書き込みます.
+func (m mysql) WriteReleaseSavepoint(w Writer, name string) error {
+	w.WriteString("RELEASE SAVEPOINT ")
+	return m.WriteIdent(w, name)
+}
+
+// IsRetryable はデッドロック (1213) とロック待ちタイムアウト (1205) のとき true を返します.
+func (mysql) IsRetryable(err error) bool {
+	var me *driver.MySQLError
+	if !errors.As(err, &me) {
+		return false
+	}
+	return me.Number == 1213 || me.Number == 1205
+}
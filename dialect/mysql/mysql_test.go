@@ -1,11 +1,14 @@
 package mysql
 
 import (
+	"errors"
 	"fmt"
 	"math"
 	"strings"
 	"testing"
 	"time"
+
+	driver "github.com/go-sql-driver/mysql"
 )
 
 func TestIdent(t *testing.T) {
@@ -293,3 +296,51 @@ func TestTime(t *testing.T) {
 		}
 	}
 }
+
+func TestLimit(t *testing.T) {
+	tests := []struct {
+		limit, offset int64
+		want          string
+	}{
+		{10, -1, "LIMIT 10"},
+		{10, 20, "LIMIT 10 OFFSET 20"},
+		{0, 0, "LIMIT 0 OFFSET 0"},
+		{-1, 20, "LIMIT 18446744073709551615 OFFSET 20"},
+	}
+
+	for _, te := range tests {
+		var (
+			d mysql
+			w strings.Builder
+		)
+
+		name := fmt.Sprintf("WriteLimit(%d, %d)", te.limit, te.offset)
+		err := d.WriteLimit(&w, te.limit, te.offset)
+		if err != nil || w.String() != te.want {
+			t.Errorf("%s = %q, %v, want %q", name, w.String(), err, te.want)
+		}
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		src  error
+		want bool
+	}{
+		{&driver.MySQLError{Number: 1213}, true},
+		{&driver.MySQLError{Number: 1205}, true},
+		{fmt.Errorf("exec: %w", &driver.MySQLError{Number: 1213}), true},
+		{&driver.MySQLError{Number: 1062}, false},
+		{errors.New("1213"), false},
+		{nil, false},
+	}
+
+	for _, te := range tests {
+		var d mysql
+
+		got := d.IsRetryable(te.src)
+		if got != te.want {
+			t.Errorf("IsRetryable(%v) = %v, want %v", te.src, got, te.want)
+		}
+	}
+}
@@ -61,4 +61,25 @@
 //	NOT IN (...)	T("!== @", []any{"a", "b"})	NOT IN ('a', 'b')
 //	IS NULL			T("== @", nil)				IS NULL
 //	IS NOT NULL		T("!== @", nil)				IS NOT NULL
+//
+// # ビルダー
+//
+// よく使うクエリはテンプレートを書かずにビルダーで組み立てられます.
+// ビルダーは Sqler なので T, M の $ に展開できます.
+//
+//	Select("id", "given_name").From("person").Where(T("age >= @", 20)).Limit(10)
+//
+// # 値の秘匿
+//
+// 値は SQL にリテラルとして展開されるため, ログに出力する SQL は StringifyRedacted で作成します.
+// Sensitive で包んだ値や sensitive オプションを持つフィールドの値は '<string:15>' のようなマーカーになります.
+//
+//	StringifyRedacted(T("email = @", Sensitive("foo@example.com")), RedactSensitive)	email = '<string:15>'
+//
+// # 厳格モード
+//
+// SetStrict(true) にすると $ などで展開できる Sqler をこのパッケージが生成したものに制限します.
+// 任意の SQL 文字列を展開するには Raw または Unsafe で明示します.
+// テンプレートが定数でない StringSqler, T, M の呼び出しは sqlbvet コマンドで検出できます.
+// sqlbvet はプレースホルダと引数の不一致など T, M の誤った使い方も検出します.
 package sqlb
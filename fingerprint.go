@@ -0,0 +1,276 @@
+package sqlb
+
+import (
+	"hash/fnv"
+	"strings"
+)
+
+// Fingerprint は SQL を正規化したフィンガープリントとそのハッシュ値を返します.
+//
+// フィンガープリントは次のように正規化します.
+//
+//   - 文字列, 数値, X'..' などのリテラルや TRUE, FALSE, NULL, プレースホルダを ? に置き換えます.
+//     ただし IS NULL, IS NOT NULL の NULL は残します.
+//   - ? だけのリスト (IN (1, 2, 3) など) を (?+) にまとめます.
+//   - VALUES に続く同じ形の行を 1 つにまとめます.
+//   - コメントを削除し, Compact と同じように空白をまとめます.
+//     括弧の内側とカンマの前の空白は削除し, カンマの後には空白を 1 つ置きます.
+//
+// ハッシュ値はフィンガープリントの FNV-1a 64 ビットハッシュです.
+func Fingerprint(query string) (string, uint64) {
+	fp := fingerprint(query)
+	h := fnv.New64a()
+	h.Write([]byte(fp))
+	return fp, h.Sum64()
+}
+
+// FingerprintSqler は sqler を文字列化して Fingerprint を返します.
+func FingerprintSqler(sqler Sqler) (string, uint64, error) {
+	s, err := Stringify(sqler)
+	if err != nil {
+		return "", 0, err
+	}
+	fp, h := Fingerprint(s)
+	return fp, h, nil
+}
+
+func isIdentChar(c byte) bool {
+	return c == '_' || c == '$' || c >= 0x80 ||
+		'0' <= c && c <= '9' || 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z'
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// fingerprinter はフィンガープリントを組み立てます.
+type fingerprinter struct {
+	d     []byte
+	space bool  // 次のトークンの前に空白が必要
+	paren []int // 開き括弧の位置
+	words [2]string
+}
+
+func fingerprint(s string) string {
+	f := fingerprinter{d: make([]byte, 0, len(s))}
+	l := len(s)
+	for p := 0; p < l; {
+		c := s[p]
+		switch {
+		case isSpace(c):
+			f.space = true
+			p++
+
+		case c == '-' && p+1 < l && s[p+1] == '-':
+			// 行コメント
+			for p += 2; p < l && s[p] != '\n'; p++ {
+			}
+			f.space = true
+
+		case c == '/' && p+1 < l && s[p+1] == '*':
+			// ブロックコメント
+			if q := strings.Index(s[p+2:], "*/"); q >= 0 {
+				p += q + 4
+			} else {
+				p = l
+			}
+			f.space = true
+
+		case c == '\'':
+			p = skipString(s, p)
+			f.literal()
+
+		case (c == 'X' || c == 'x' || c == 'B' || c == 'b' || c == 'N' || c == 'n') &&
+			p+1 < l && s[p+1] == '\'' && (p == 0 || !isIdentChar(s[p-1])):
+			// X'..', B'..', N'..'
+			p = skipString(s, p+1)
+			f.literal()
+
+		case c == '`' || c == '"':
+			// 引用符付きの識別子
+			q := strings.IndexByte(s[p+1:], c)
+			if q < 0 {
+				q = l - p - 2
+			}
+			f.token(s[p:p+q+2], false)
+			p += q + 2
+
+		case isDigit(c) || c == '.' && p+1 < l && isDigit(s[p+1]):
+			p = skipNumber(s, p)
+			f.literal()
+
+		case c == '-' && p+1 < l && isDigit(s[p+1]) && f.operand():
+			// 負の数値
+			p = skipNumber(s, p+1)
+			f.literal()
+
+		case c == '?':
+			p++
+			f.literal()
+
+		case c == '$' && p+1 < l && isDigit(s[p+1]):
+			// $1 形式のプレースホルダ
+			for p++; p < l && isDigit(s[p]); p++ {
+			}
+			f.literal()
+
+		case isIdentChar(c):
+			q := p + 1
+			for ; q < l && isIdentChar(s[q]); q++ {
+			}
+			f.word(s[p:q])
+			p = q
+
+		default:
+			f.punct(c)
+			p++
+		}
+	}
+	return string(f.d)
+}
+
+// skipString は p にある引用符で始まる文字列リテラルの終わりの位置を返します.
+// 文字列リテラル内では引用符の重複とバックスラッシュによるエスケープを認識します.
+func skipString(s string, p int) int {
+	l := len(s)
+	for p++; p < l; p++ {
+		switch s[p] {
+		case '\\':
+			p++
+		case '\'':
+			if p+1 < l && s[p+1] == '\'' {
+				p++
+				continue
+			}
+			return p + 1
+		}
+	}
+	return l
+}
+
+// skipNumber は p から始まる数値リテラルの終わりの位置を返します.
+func skipNumber(s string, p int) int {
+	l := len(s)
+	if p+1 < l && s[p] == '0' && (s[p+1] == 'x' || s[p+1] == 'X') {
+		for p += 2; p < l && isIdentChar(s[p]); p++ {
+		}
+		return p
+	}
+	for ; p < l && (isDigit(s[p]) || s[p] == '.'); p++ {
+	}
+	if p < l && (s[p] == 'e' || s[p] == 'E') {
+		q := p + 1
+		if q < l && (s[q] == '+' || s[q] == '-') {
+			q++
+		}
+		if q < l && isDigit(s[q]) {
+			for p = q; p < l && isDigit(s[p]); p++ {
+			}
+		}
+	}
+	return p
+}
+
+// operand は次のトークンが被演算子の位置にあるかを返します.
+func (f *fingerprinter) operand() bool {
+	if len(f.d) == 0 {
+		return true
+	}
+	return strings.IndexByte("(,=<>+-*/%", f.d[len(f.d)-1]) >= 0
+}
+
+func (f *fingerprinter) token(t string, word bool) {
+	if f.space && len(f.d) > 0 && f.d[len(f.d)-1] != '(' {
+		f.d = append(f.d, ' ')
+	}
+	f.space = false
+	f.d = append(f.d, t...)
+	if word {
+		f.words[0], f.words[1] = f.words[1], strings.ToUpper(t)
+	} else {
+		f.words[0], f.words[1] = f.words[1], ""
+	}
+}
+
+func (f *fingerprinter) literal() {
+	f.token("?", false)
+}
+
+func (f *fingerprinter) word(w string) {
+	switch strings.ToUpper(w) {
+	case "TRUE", "FALSE":
+		f.literal()
+		return
+	case "NULL":
+		if f.words[1] != "IS" && !(f.words[0] == "IS" && f.words[1] == "NOT") {
+			f.literal()
+			return
+		}
+	}
+	f.token(w, true)
+}
+
+func (f *fingerprinter) punct(c byte) {
+	switch c {
+	case '(':
+		f.token("(", false)
+		f.paren = append(f.paren, len(f.d)-1)
+
+	case ')':
+		f.space = false
+		if n := len(f.paren); n > 0 {
+			open := f.paren[n-1]
+			f.paren = f.paren[:n-1]
+			if isPlaceholderList(f.d[open+1:]) {
+				f.d = append(f.d[:open+1], "?+"...)
+			}
+			f.token(")", false)
+			f.collapseRows(open)
+		} else {
+			f.token(")", false)
+		}
+
+	case ',':
+		f.space = false
+		f.token(",", false)
+		f.space = true
+
+	default:
+		f.token(string(c), false)
+	}
+}
+
+// isPlaceholderList は b が ? をカンマで区切ったリストかを返します.
+func isPlaceholderList(b []byte) bool {
+	if len(b) == 0 {
+		return false
+	}
+	for _, c := range b {
+		if c != '?' && c != ',' && c != ' ' {
+			return false
+		}
+	}
+	return true
+}
+
+// collapseRows は VALUES に続く行 d[open:] が直前の行と同じならまとめます.
+func (f *fingerprinter) collapseRows(open int) {
+	row := f.d[open:]
+	prev := open - 2 - len(row)
+	if prev < 0 || string(f.d[open-2:open]) != ", " || string(f.d[prev:open-2]) != string(row) {
+		return
+	}
+	if !rowsFollowValues(f.d[:prev]) {
+		return
+	}
+	f.d = f.d[:open-2]
+}
+
+// rowsFollowValues は b が VALUES で終わるかを返します.
+func rowsFollowValues(b []byte) bool {
+	s := strings.TrimRight(string(b), " ")
+	if len(s) < 6 || !strings.EqualFold(s[len(s)-6:], "VALUES") {
+		return false
+	}
+	return len(s) == 6 || !isIdentChar(s[len(s)-7])
+}
@@ -0,0 +1,66 @@
+package sqlb
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFingerprint(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{"", ""},
+		{"SELECT\n\t*\nFROM  t", "SELECT * FROM t"},
+		{"SELECT * FROM t WHERE id = 123", "SELECT * FROM t WHERE id = ?"},
+		{"SELECT * FROM t WHERE id = -1.5e+3", "SELECT * FROM t WHERE id = ?"},
+		{"SELECT a - 1 FROM t", "SELECT a - ? FROM t"},
+		{"SELECT * FROM t2 WHERE c1 = 0x1F", "SELECT * FROM t2 WHERE c1 = ?"},
+		{`SELECT * FROM t WHERE name = 'foo\'s' AND memo = 'it''s'`, "SELECT * FROM t WHERE name = ? AND memo = ?"},
+		{"SELECT * FROM t WHERE data = X'414243' OR data = b'01'", "SELECT * FROM t WHERE data = ? OR data = ?"},
+		{"SELECT * FROM t WHERE at < '2001-01-02 13:14:15.678901'", "SELECT * FROM t WHERE at < ?"},
+		{"SELECT * FROM t WHERE flag = TRUE AND deleted = false", "SELECT * FROM t WHERE flag = ? AND deleted = ?"},
+		{"SELECT * FROM t WHERE a IS NULL AND b IS NOT NULL AND c = NULL", "SELECT * FROM t WHERE a IS NULL AND b IS NOT NULL AND c = ?"},
+		{"SELECT * FROM t WHERE id = ? OR id = $2", "SELECT * FROM t WHERE id = ? OR id = ?"},
+		{"SELECT * FROM t WHERE id IN (1,2, 3)", "SELECT * FROM t WHERE id IN (?+)"},
+		{"SELECT * FROM t WHERE id IN ( 1 )", "SELECT * FROM t WHERE id IN (?+)"},
+		{"SELECT * FROM t WHERE (a, b) IN ((1, 2), (3, 4))", "SELECT * FROM t WHERE (a, b) IN ((?+), (?+))"},
+		{"SELECT COUNT(*) FROM t", "SELECT COUNT(*) FROM t"},
+		{"INSERT INTO t (a, b) VALUES (1, 'x'), (2, 'y'), (3, 'z')", "INSERT INTO t (a, b) VALUES (?+)"},
+		{"INSERT INTO t (a, b) VALUES (1, NOW()), (2, NOW())", "INSERT INTO t (a, b) VALUES (?, NOW())"},
+		{"INSERT INTO t (a) VALUES (1) ON DUPLICATE KEY UPDATE a = VALUES(a), b = VALUES(b)", "INSERT INTO t (a) VALUES (?+) ON DUPLICATE KEY UPDATE a = VALUES(a), b = VALUES(b)"},
+		{"SELECT `a1`, \"b 2\" FROM t2", "SELECT `a1`, \"b 2\" FROM t2"},
+		{"SELECT 1 -- comment\nFROM t /* block */ WHERE x = 2", "SELECT ? FROM t WHERE x = ?"},
+	}
+
+	for _, te := range tests {
+		got, _ := Fingerprint(te.src)
+		if got != te.want {
+			t.Errorf("Fingerprint(%q) = %q, want %q", te.src, got, te.want)
+		}
+	}
+}
+
+func TestFingerprintHash(t *testing.T) {
+	_, h1 := Fingerprint("SELECT * FROM t WHERE id IN (1, 2)")
+	_, h2 := Fingerprint("select * from t where id in (3)")
+	_, h3 := Fingerprint("SELECT * FROM t WHERE id IN (3)")
+	if h1 == h2 {
+		t.Errorf("%s: hash of different fingerprints equal", "test FingerprintHash")
+	}
+	if h1 != h3 {
+		t.Errorf("%s: hash of same fingerprints %x, %x", "test FingerprintHash", h1, h3)
+	}
+}
+
+func TestFingerprintSqler(t *testing.T) {
+	at := time.Date(2001, time.January, 2, 13, 14, 15, 0, time.UTC)
+	got, _, err := FingerprintSqler(T("SELECT * FROM t WHERE id IN (@) AND at < @ AND data = @", []any{1, 2}, at, []byte("abc")))
+	want := "SELECT * FROM t WHERE id IN (?+) AND at < ? AND data = ?"
+	if err != nil {
+		t.Fatalf("%s errored %v", "test FingerprintSqler", err)
+	}
+	if got != want {
+		t.Errorf("%s = %q, want %q", "test FingerprintSqler", got, want)
+	}
+}
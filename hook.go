@@ -0,0 +1,225 @@
+package sqlb
+
+import (
+	"context"
+	"database/sql"
+	"sync/atomic"
+	"time"
+
+	"github.com/17e10/go-sqlb/sqlt"
+)
+
+// QueryEvent は実行する SQL の情報です.
+type QueryEvent struct {
+	Op           string        // "query", "queryrow", "exec", "prepare"
+	Query        string        // 実行する SQL
+	Args         []any         // SQL の引数
+	Start        time.Time     // 実行を開始した時刻
+	Duration     time.Duration // 実行にかかった時間. After でのみ有効です
+	RowsAffected int64         // Exec で影響を受けた行数. After でのみ有効です
+	Err          error         // 実行のエラー. After でのみ有効です
+}
+
+// Hook は SQL の実行前後に呼び出されるインターフェイスです.
+//
+// Before は実行前に呼び出され, 実行と After に渡す context を返します.
+// After は実行後に呼び出されます.
+// 複数の Hook があるとき Before は登録順に, After は逆順に呼び出されます.
+type Hook interface {
+	Before(ctx context.Context, e *QueryEvent) context.Context
+	After(ctx context.Context, e *QueryEvent)
+}
+
+// HookFuncs は関数を Hook として使用できるようにするアダプタです.
+//
+// nil の関数は呼び出しません.
+type HookFuncs struct {
+	BeforeFunc func(ctx context.Context, e *QueryEvent) context.Context
+	AfterFunc  func(ctx context.Context, e *QueryEvent)
+}
+
+// Before は h.BeforeFunc を呼び出します.
+func (h HookFuncs) Before(ctx context.Context, e *QueryEvent) context.Context {
+	if h.BeforeFunc == nil {
+		return ctx
+	}
+	return h.BeforeFunc(ctx, e)
+}
+
+// After は h.AfterFunc を呼び出します.
+func (h HookFuncs) After(ctx context.Context, e *QueryEvent) {
+	if h.AfterFunc != nil {
+		h.AfterFunc(ctx, e)
+	}
+}
+
+// hooks は Query, QueryRow, Exec が呼び出す Hook です.
+var hooks atomic.Pointer[[]Hook]
+
+// AddHook は Query, QueryRow, Exec が呼び出す Hook を追加します.
+func AddHook(h Hook) {
+	for {
+		old := hooks.Load()
+		var hs []Hook
+		if old != nil {
+			hs = appendClone(*old, h)
+		} else {
+			hs = []Hook{h}
+		}
+		if hooks.CompareAndSwap(old, &hs) {
+			return
+		}
+	}
+}
+
+// SetHooks は Query, QueryRow, Exec が呼び出す Hook を置き換えます.
+//
+// 引数を省略するとすべての Hook を取り除きます.
+func SetHooks(hs ...Hook) {
+	hs = appendClone(hs)
+	hooks.Store(&hs)
+}
+
+// globalHooks は Query, QueryRow, Exec が呼び出す Hook を返します.
+func globalHooks() []Hook {
+	if hs := hooks.Load(); hs != nil {
+		return *hs
+	}
+	return nil
+}
+
+// beforeHooks は Hook の Before を登録順に呼び出します.
+func beforeHooks(ctx context.Context, hs []Hook, e *QueryEvent) context.Context {
+	e.Start = time.Now()
+	for _, h := range hs {
+		ctx = h.Before(ctx, e)
+	}
+	return ctx
+}
+
+// afterHooks は Hook の After を逆順に呼び出します.
+func afterHooks(ctx context.Context, hs []Hook, e *QueryEvent, err error) {
+	e.Duration = time.Since(e.Start)
+	e.Err = err
+	for i := len(hs) - 1; i >= 0; i-- {
+		hs[i].After(ctx, e)
+	}
+}
+
+// queryHooked は Hook を呼び出しながら QueryContext を実行します.
+func queryHooked(ctx context.Context, hs []Hook, conn sqlt.Queryer, query string, args []any) (*sql.Rows, error) {
+	if len(hs) == 0 {
+		return conn.QueryContext(ctx, query, args...)
+	}
+	e := &QueryEvent{Op: "query", Query: query, Args: args}
+	ctx = beforeHooks(ctx, hs, e)
+	rows, err := conn.QueryContext(ctx, query, args...)
+	afterHooks(ctx, hs, e, err)
+	return rows, err
+}
+
+// queryRowHooked は Hook を呼び出しながら QueryRowContext を実行します.
+func queryRowHooked(ctx context.Context, hs []Hook, conn sqlt.QueryRower, query string, args []any) *sql.Row {
+	if len(hs) == 0 {
+		return conn.QueryRowContext(ctx, query, args...)
+	}
+	e := &QueryEvent{Op: "queryrow", Query: query, Args: args}
+	ctx = beforeHooks(ctx, hs, e)
+	row := conn.QueryRowContext(ctx, query, args...)
+	var err error
+	if row != nil {
+		err = row.Err()
+	}
+	afterHooks(ctx, hs, e, err)
+	return row
+}
+
+// execHooked は Hook を呼び出しながら ExecContext を実行します.
+func execHooked(ctx context.Context, hs []Hook, conn sqlt.Execer, query string, args []any) (sql.Result, error) {
+	if len(hs) == 0 {
+		return conn.ExecContext(ctx, query, args...)
+	}
+	e := &QueryEvent{Op: "exec", Query: query, Args: args}
+	ctx = beforeHooks(ctx, hs, e)
+	res, err := conn.ExecContext(ctx, query, args...)
+	if err == nil {
+		e.RowsAffected, _ = res.RowsAffected()
+	}
+	afterHooks(ctx, hs, e, err)
+	return res, err
+}
+
+// HookedConn は Hook を呼び出しながら SQL を実行する sqlt.Conn です.
+//
+// HookedConn の Hook は AddHook で登録した Hook とは別に呼び出されます.
+//
+//	conn := sqlb.WithHooks(db, logHook, traceHook)
+//	rows, err := sqlb.Query(conn, ctx, q)
+type HookedConn struct {
+	conn  sqlt.Conn
+	hooks []Hook
+}
+
+// WithHooks は conn を hs を呼び出す HookedConn でラップします.
+func WithHooks(conn sqlt.Conn, hs ...Hook) *HookedConn {
+	return &HookedConn{conn, hs}
+}
+
+// Unwrap はラップしている sqlt.Conn を返します.
+func (c *HookedConn) Unwrap() sqlt.Conn {
+	return c.conn
+}
+
+// PrepareContext は Hook を呼び出しながら conn.PrepareContext を呼び出します.
+func (c *HookedConn) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	if len(c.hooks) == 0 {
+		return c.conn.PrepareContext(ctx, query)
+	}
+	e := &QueryEvent{Op: "prepare", Query: query}
+	ctx = beforeHooks(ctx, c.hooks, e)
+	stmt, err := c.conn.PrepareContext(ctx, query)
+	afterHooks(ctx, c.hooks, e, err)
+	return stmt, err
+}
+
+// QueryContext は Hook を呼び出しながら conn.QueryContext を呼び出します.
+func (c *HookedConn) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return queryHooked(ctx, c.hooks, c.conn, query, args)
+}
+
+// QueryRowContext は Hook を呼び出しながら conn.QueryRowContext を呼び出します.
+func (c *HookedConn) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return queryRowHooked(ctx, c.hooks, c.conn, query, args)
+}
+
+// ExecContext は Hook を呼び出しながら conn.ExecContext を呼び出します.
+func (c *HookedConn) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return execHooked(ctx, c.hooks, c.conn, query, args)
+}
+
+// BeginTx は conn でトランザクションを開始し, 同じ Hook を呼び出す HookedTx を返します.
+//
+// conn が BeginTx を持たないときはエラーを返します.
+func (c *HookedConn) BeginTx(ctx context.Context, opts *sql.TxOptions) (sqlt.Tx, error) {
+	tx, err := beginTx(ctx, c.conn, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &HookedTx{HookedConn{tx, c.hooks}, tx}, nil
+}
+
+// HookedTx は Hook を呼び出しながら SQL を実行するトランザクションです.
+type HookedTx struct {
+	HookedConn
+	tx sqlt.Tx
+}
+
+// Commit はトランザクションをコミットします.
+func (tx *HookedTx) Commit() error {
+	return tx.tx.Commit()
+}
+
+// Rollback はトランザクションをロールバックします.
+func (tx *HookedTx) Rollback() error {
+	return tx.tx.Rollback()
+}
@@ -0,0 +1,125 @@
+package sqlb
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/17e10/go-sqlb/sqlt"
+)
+
+type ctxKey string
+
+// recordHook は呼び出された順序と QueryEvent を記録する Hook を作成します.
+func recordHook(name string, log *[]string, events *[]QueryEvent) Hook {
+	return HookFuncs{
+		BeforeFunc: func(ctx context.Context, e *QueryEvent) context.Context {
+			*log = append(*log, name+" before "+e.Op)
+			return context.WithValue(ctx, ctxKey(name), true)
+		},
+		AfterFunc: func(ctx context.Context, e *QueryEvent) {
+			if ctx.Value(ctxKey(name)) != true {
+				*log = append(*log, name+" lost context")
+			}
+			*log = append(*log, name+" after "+e.Op)
+			if events != nil {
+				*events = append(*events, *e)
+			}
+		},
+	}
+}
+
+func TestHooks(t *testing.T) {
+	var (
+		log    []string
+		events []QueryEvent
+	)
+	SetHooks(recordHook("a", &log, &events), recordHook("b", &log, nil))
+	defer SetHooks()
+
+	ctx := context.TODO()
+	execer := &sqlt.TestExecer{RowsAffected: 3}
+	if _, err := Exec(execer, ctx, T("DELETE FROM t WHERE id = @", 1)); err != nil {
+		t.Fatalf("%s errored %v", "test Hooks", err)
+	}
+
+	want := []string{"a before exec", "b before exec", "b after exec", "a after exec"}
+	if !reflect.DeepEqual(log, want) {
+		t.Errorf("%s log %q, want %q", "test Hooks", log, want)
+	}
+	if len(events) != 1 {
+		t.Fatalf("%s %d events, want 1", "test Hooks", len(events))
+	}
+	e := events[0]
+	if e.Query != "DELETE FROM t WHERE id = 1" || e.RowsAffected != 3 || e.Err != nil || e.Start.IsZero() {
+		t.Errorf("%s event %+v", "test Hooks", e)
+	}
+
+	// Stringify に失敗したときは Hook を呼び出さない
+	log = nil
+	Exec(execer, ctx, T("#", 1))
+	if len(log) != 0 {
+		t.Errorf("%s log %q, want empty", "test Hooks", log)
+	}
+}
+
+func TestHookedConn(t *testing.T) {
+	var (
+		log    []string
+		events []QueryEvent
+	)
+	AddHook(recordHook("global", &log, nil))
+	defer SetHooks()
+
+	ctx := context.TODO()
+	conn := WithHooks(&testTx{}, recordHook("conn", &log, &events))
+	Exec(conn, ctx, T("a"))
+	conn.QueryContext(ctx, "b", 1)
+
+	want := []string{
+		"global before exec", "conn before exec", "conn after exec", "global after exec",
+		"conn before query", "conn after query",
+	}
+	if !reflect.DeepEqual(log, want) {
+		t.Errorf("%s log %q, want %q", "test HookedConn", log, want)
+	}
+	if len(events) != 2 || events[1].Query != "b" || !reflect.DeepEqual(events[1].Args, []any{1}) || events[1].Err == nil {
+		t.Errorf("%s events %+v", "test HookedConn", events)
+	}
+}
+
+func TestHookedConnBeginTx(t *testing.T) {
+	var log []string
+
+	ctx := context.TODO()
+	tc := &sqlt.TestConn{RowsAffected: 1}
+	conn := WithHooks(tc, recordHook("conn", &log, nil))
+	err := InTx(ctx, conn, nil, func(tx sqlt.Conn) error {
+		_, err := Exec(tx, ctx, T("a"))
+		return err
+	})
+	if err != nil {
+		t.Fatalf("%s errored %v", "test HookedConn InTx", err)
+	}
+	rows := []struct{ Name string }{{"a"}, {"b"}}
+	n, err := InsertBatches(conn, ctx, "person", rows, BatchOptions{MaxRows: 1, Tx: true})
+	if err != nil || n != 2 {
+		t.Fatalf("%s = %d, %v, want 2", "test HookedConn InsertBatches", n, err)
+	}
+
+	want := []string{
+		"conn before exec", "conn after exec",
+		"conn before exec", "conn after exec", "conn before exec", "conn after exec",
+	}
+	if !reflect.DeepEqual(log, want) {
+		t.Errorf("%s log %q, want %q", "test HookedConn BeginTx", log, want)
+	}
+	if tc.Begun != 2 || tc.Committed != 2 || len(tc.Execed) != 3 {
+		t.Errorf("%s begun %d, committed %d, execed %d, want 2, 2, 3", "test HookedConn BeginTx",
+			tc.Begun, tc.Committed, len(tc.Execed))
+	}
+
+	if _, err := WithHooks(&testTx{}).BeginTx(ctx, nil); err != errNoBeginner {
+		t.Errorf("%s errored %v, want %v", "test HookedConn BeginTx", err, errNoBeginner)
+	}
+}
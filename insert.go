@@ -0,0 +1,234 @@
+package sqlb
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var errColumnMismatch = errors.New("column set mismatch")
+
+// InsertBuilder は INSERT 文を組み立てる Sqler です.
+//
+// InsertBuilder は SelectBuilder と同様に不変です.
+//
+//	q := sqlb.Insert("person").Structs(persons, "id")
+type InsertBuilder struct {
+	table     string
+	columns   []string
+	rows      []insertRow
+	ignore    bool
+	returning []string
+	err       error
+}
+
+// insertRow は INSERT する 1 行を表します.
+type insertRow struct {
+	columns []string
+	values  []any
+}
+
+// Insert は table に INSERT する InsertBuilder を作成します.
+func Insert(table string) *InsertBuilder {
+	return &InsertBuilder{table: table}
+}
+
+// Columns は Values で追加する行のカラムリストを指定します.
+func (b *InsertBuilder) Columns(columns ...string) *InsertBuilder {
+	c := *b
+	c.columns = columns
+	return &c
+}
+
+// Values は Columns で指定したカラムの値リストを 1 行追加します.
+func (b *InsertBuilder) Values(values ...any) *InsertBuilder {
+	c := *b
+	c.rows = appendClone(c.rows, insertRow{b.columns, values})
+	return &c
+}
+
+// Struct は構造体 v を 1 行追加します.
+//
+// v は構造体または構造体のポインタです.
+// excludes で除外するカラムを指定できます.
+func (b *InsertBuilder) Struct(v any, excludes ...string) *InsertBuilder {
+	return b.addStructs(reflect.ValueOf(v), false, excludes)
+}
+
+// Structs は構造体の配列 v を行として追加します.
+//
+// v は構造体または構造体のポインタの配列です.
+// excludes で除外するカラムを指定できます.
+func (b *InsertBuilder) Structs(v any, excludes ...string) *InsertBuilder {
+	return b.addStructs(reflect.ValueOf(v), true, excludes)
+}
+
+func (b *InsertBuilder) addStructs(rv reflect.Value, many bool, excludes []string) *InsertBuilder {
+	c := *b
+	if c.err != nil {
+		return &c
+	}
+
+	var rows []insertRow
+	if many {
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			c.err = fmt.Errorf("got type %s: %w", rv.Type(), errNoStruct)
+			return &c
+		}
+		rows = make([]insertRow, 0, rv.Len())
+		for i, l := 0, rv.Len(); i < l; i++ {
+			row, err := structRow(rv.Index(i), excludes)
+			if err != nil {
+				c.err = err
+				return &c
+			}
+			rows = append(rows, row)
+		}
+	} else {
+		row, err := structRow(rv, excludes)
+		if err != nil {
+			c.err = err
+			return &c
+		}
+		rows = append(rows, row)
+	}
+	c.rows = appendClone(c.rows, rows...)
+	return &c
+}
+
+// structRow は構造体 rv から INSERT する 1 行を作成します.
+func structRow(rv reflect.Value, excludes []string) (insertRow, error) {
+	if rv.Kind() == reflect.Pointer {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return insertRow{}, fmt.Errorf("got type %s: %w", rv.Type(), errNoStruct)
+	}
+	cols := excludeColumns(typeColumnInfos(rv.Type()), excludes)
+	row := insertRow{make([]string, len(cols)), make([]any, len(cols))}
+	for i, c := range cols {
+		row.columns[i] = c.name
+		row.values[i] = c.value(rv)
+	}
+	return row, nil
+}
+
+// Ignore は重複するキーを持つ行を無視します.
+//
+// MySQL では INSERT IGNORE に, PostgreSQL などでは ON CONFLICT DO NOTHING になります.
+func (b *InsertBuilder) Ignore() *InsertBuilder {
+	c := *b
+	c.ignore = true
+	return &c
+}
+
+// Returning は挿入した行の columns を返すようにします.
+//
+// PostgreSQL などでは RETURNING 句に, SQL Server では OUTPUT INSERTED 句になります.
+// columns を省略するとすべてのカラムを返します.
+func (b *InsertBuilder) Returning(columns ...string) *InsertBuilder {
+	c := *b
+	c.returning = append([]string{}, columns...)
+	return &c
+}
+
+// Sql は INSERT 文を展開します.
+func (b *InsertBuilder) Sql(w Writer) error {
+	if err := b.sql(w); err != nil {
+		return fmt.Errorf("insert: %w", err)
+	}
+	return nil
+}
+
+func (b *InsertBuilder) sql(w Writer) error {
+	verb, suffix := "INSERT", ""
+	if b.ignore {
+		verb, suffix = dialect().InsertIgnore()
+	}
+	if _, err := b.writeInsert(w, verb); err != nil {
+		return err
+	}
+	w.WriteString(suffix)
+	return putReturning(w, b.returning)
+}
+
+// writeInsert は verb INTO table (columns) VALUES (...), ... を展開し, カラムリストを返します.
+func (b *InsertBuilder) writeInsert(w Writer, verb string) ([]string, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	cols, err := b.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	w.WriteString(verb)
+	w.WriteString(" INTO ")
+	if err := writeIdent(w, b.table); err != nil {
+		return nil, err
+	}
+	if len(cols) > 0 {
+		w.WriteString(" (")
+		if err := putIdentList(w, cols); err != nil {
+			return nil, err
+		}
+		w.WriteByte(')')
+	}
+	if err := putOutput(w, b.returning, "INSERTED."); err != nil {
+		return nil, err
+	}
+	w.WriteString(" VALUES ")
+	if err := b.writeRows(w); err != nil {
+		return nil, err
+	}
+	return cols, nil
+}
+
+// writeRows は行を (value1, value2, ...), ... に展開します.
+func (b *InsertBuilder) writeRows(w Writer) error {
+	for i, row := range b.rows {
+		if i == 0 {
+			w.WriteByte('(')
+		} else {
+			w.WriteString(", (")
+		}
+		if err := putValueList(w, row.values); err != nil {
+			return err
+		}
+		w.WriteByte(')')
+	}
+	return nil
+}
+
+// validate はすべての行が同じカラムの組を持つことを確かめ, そのカラムリストを返します.
+func (b *InsertBuilder) validate() ([]string, error) {
+	if len(b.rows) == 0 {
+		return nil, errEmptySlice
+	}
+	cols := b.rows[0].columns
+	for i, row := range b.rows {
+		if !equalColumns(row.columns, cols) {
+			return nil, fmt.Errorf("row %d: %v, want %v: %w", i, row.columns, cols, errColumnMismatch)
+		}
+		if len(cols) > 0 && len(row.values) != len(cols) {
+			return nil, fmt.Errorf("row %d: %d values, want %d: %w", i, len(row.values), len(cols), errColumnMismatch)
+		}
+		if len(row.values) != len(b.rows[0].values) {
+			return nil, fmt.Errorf("row %d: %d values, want %d: %w", i, len(row.values), len(b.rows[0].values), errColumnMismatch)
+		}
+	}
+	return cols, nil
+}
+
+// equalColumns は x と y が同じカラムリストか調べます.
+func equalColumns(x, y []string) bool {
+	if len(x) != len(y) {
+		return false
+	}
+	for i := range x {
+		if x[i] != y[i] {
+			return false
+		}
+	}
+	return true
+}
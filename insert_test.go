@@ -0,0 +1,64 @@
+package sqlb
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestInsert(t *testing.T) {
+	type nick struct {
+		Id       uint64
+		Nickname string `sqlb:"nickname,nullzero"`
+	}
+
+	tests := []struct {
+		sqler Sqler
+		want  string
+		err   string
+	}{
+		{Insert("person").Struct(&olivia, "id"),
+			"INSERT INTO `person` (`family_name`, `given_name`, `age`) VALUES ('Williams', 'Olivia', 54)", ""},
+		{Insert("person").Structs(persons, "id"),
+			"INSERT INTO `person` (`family_name`, `given_name`, `age`) VALUES ('Williams', 'Olivia', 54), ('Loggins', 'Kenny', 75)", ""},
+		{Insert("person").Structs([]*person{&olivia}, "id", "age").Ignore(),
+			"INSERT IGNORE INTO `person` (`family_name`, `given_name`) VALUES ('Williams', 'Olivia')", ""},
+		{Insert("nick").Struct(nick{1, ""}),
+			"INSERT INTO `nick` (`id`, `nickname`) VALUES (1, NULL)", ""},
+		{Insert("person").Columns("given_name", "age").Values("Olivia", 54).Values("Kenny", 75),
+			"INSERT INTO `person` (`given_name`, `age`) VALUES ('Olivia', 54), ('Kenny', 75)", ""},
+		{Insert("person").Values(1, "Olivia"),
+			"INSERT INTO `person` VALUES (1, 'Olivia')", ""},
+		{Insert("person"), "", "insert: empty array or slice"},
+		{Insert("person").Struct(&olivia).Struct(&kenny, "id"), "",
+			"insert: row 1: [family_name given_name age], want [id family_name given_name age]: column set mismatch"},
+		{Insert("person").Columns("given_name", "age").Values("Olivia"), "",
+			"insert: row 0: 1 values, want 2: column set mismatch"},
+		{Insert("person").Struct(123), "", "insert: got type int: no struct"},
+	}
+
+	for i, te := range tests {
+		var goterr string
+
+		name := fmt.Sprintf("test Insert #%d", i)
+		got, err := Stringify(te.sqler)
+		if err != nil {
+			goterr = err.Error()
+		}
+		if goterr != te.err {
+			t.Errorf("%s errored %q, want %q", name, goterr, te.err)
+		}
+		if got != te.want {
+			t.Errorf("%s returned %q, want %q", name, got, te.want)
+		}
+	}
+}
+
+func TestInsertIgnoreDialect(t *testing.T) {
+	useDialect(t, pgDialect{dialect()})
+
+	got, err := Stringify(Insert("person").Columns("id").Values(1).Ignore())
+	want := "INSERT INTO `person` (`id`) VALUES (1) ON CONFLICT DO NOTHING"
+	if err != nil || got != want {
+		t.Errorf("%s = %q, %v, want %q", "test Insert ignore", got, err, want)
+	}
+}
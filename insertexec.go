@@ -0,0 +1,322 @@
+package sqlb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	d "github.com/17e10/go-sqlb/dialect"
+	"github.com/17e10/go-sqlb/sqlt"
+)
+
+// InsertReturning は rows を table に INSERT し, columns の値を rows の各構造体に書き戻します.
+//
+// auto オプションを持つカラムは INSERT しません.
+// columns を省略すると auto オプションを持つカラムを書き戻します.
+// 書き戻すカラムがないときはエラーを返します.
+// 返された行数が rows と異なるときもエラーを返します.
+//
+// RETURNING, OUTPUT 句を持たない MySQL では LastInsertId から求めた値を
+// auto オプションを持つカラムに書き込みます.
+// このとき columns に auto オプションを持たないカラムを指定するとエラーを返します.
+//
+//	type person struct {
+//		Id   uint64 `sqlb:"id,pk,auto"`
+//		Name string
+//	}
+//	err := sqlb.InsertReturning(conn, ctx, "person", persons)
+func InsertReturning[V any](conn sqlt.QueryExecer, ctx context.Context, table string, rows []V, columns ...string) error {
+	if len(rows) == 0 {
+		return errEmptySlice
+	}
+	autos := autoColumnNames(getColumnInfos((*V)(nil)))
+	if len(columns) == 0 {
+		columns = autos
+	}
+	if len(columns) == 0 {
+		return fmt.Errorf("returning: %w", errEmptySlice)
+	}
+
+	if dialect().ReturningStyle() == d.ReturningNone {
+		for _, c := range columns {
+			if !containsString(autos, c) {
+				return fmt.Errorf("returning column %s: %w", c, errNoReturning)
+			}
+		}
+		_, err := InsertStructs(conn, ctx, table, rows, 1)
+		return err
+	}
+
+	r, err := Query(conn, ctx, Insert(table).Structs(rows, autos...).Returning(columns...))
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	i := 0
+	for ; r.Next(); i++ {
+		if i >= len(rows) {
+			return fmt.Errorf("returning row %d: %w", i, errOutRange)
+		}
+		if err = scanColumns(r, &rows[i], columns); err != nil {
+			return err
+		}
+	}
+	if err = r.Err(); err != nil {
+		return err
+	}
+	if i != len(rows) {
+		return fmt.Errorf("returning %d rows for %d: %w", i, len(rows), errOutRange)
+	}
+	return nil
+}
+
+// InsertStructs は rows を table に INSERT し, 生成された id を rows の各構造体に書き込みます.
+//
+// auto オプションを持つカラムは INSERT せず, LastInsertId から求めた値を書き込みます.
+// MySQL の LastInsertId は複数行 INSERT で最初の行の id を返すので,
+// 2 行目以降には increment ずつ増やした値を書き込みます.
+// increment には auto_increment_increment の値を指定します. 0 以下のときは 1 として扱います.
+func InsertStructs[V any](conn sqlt.Execer, ctx context.Context, table string, rows []V, increment int64) (sql.Result, error) {
+	if len(rows) == 0 {
+		return nil, errEmptySlice
+	}
+	if increment <= 0 {
+		increment = 1
+	}
+
+	autos := autoColumnNames(getColumnInfos((*V)(nil)))
+	res, err := Exec(conn, ctx, Insert(table).Structs(rows, autos...))
+	if err != nil {
+		return nil, err
+	}
+	if err = assignInsertIds(rows, res, increment); err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
+// autoColumnNames は auto オプションを持つカラム名を返します.
+func autoColumnNames(cols []columnInfo) []string {
+	var r []string
+	for _, c := range cols {
+		if c.auto {
+			r = append(r, c.name)
+		}
+	}
+	return r
+}
+
+// containsString は s が v を含むかを返します.
+func containsString(s []string, v string) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
+
+// scanColumns は columns の順に並んだ結果を構造体の対応するフィールドに読み込みます.
+func scanColumns[V any](row sqlt.RowsScanner, dest *V, columns []string) error {
+	cols := getColumnInfos(dest)
+	rv := reflect.ValueOf(dest).Elem()
+	d := make([]any, len(columns))
+	for i, name := range columns {
+		c, ok := findColumn(cols, name)
+		if !ok {
+			return fmt.Errorf("column %s: %w", name, errNoSuchKey)
+		}
+		d[i] = rv.FieldByIndex(c.index).Addr().Interface()
+	}
+	return row.Scan(d...)
+}
+
+// findColumn は name という名前のカラム情報を探します.
+func findColumn(cols []columnInfo, name string) (columnInfo, bool) {
+	for _, c := range cols {
+		if c.name == name {
+			return c, true
+		}
+	}
+	return columnInfo{}, false
+}
+
+// assignInsertIds は LastInsertId を先頭として increment ずつ増える値を
+// rows の auto オプションを持つフィールドに書き込みます.
+//
+// auto オプションを持つフィールドがないときは何もしません.
+func assignInsertIds[V any](rows []V, res sql.Result, increment int64) error {
+	var auto columnInfo
+	for _, c := range getColumnInfos((*V)(nil)) {
+		if c.auto {
+			auto = c
+			break
+		}
+	}
+	if auto.index == nil {
+		return nil
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	for i := range rows {
+		fv := reflect.ValueOf(&rows[i]).Elem().FieldByIndex(auto.index)
+		v := id + int64(i)*increment
+		switch fv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			fv.SetInt(v)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			fv.SetUint(uint64(v))
+		default:
+			return fmt.Errorf("column %s got type %s: %w", auto.name, fv.Type(), errNoValueType)
+		}
+	}
+	return nil
+}
+
+// BatchOptions は InsertBatches で行を分割する方法を指定します.
+type BatchOptions struct {
+	// MaxRows は 1 つの INSERT 文に含める最大行数です. 0 のときは制限しません.
+	MaxRows int
+
+	// MaxBytes は 1 つの INSERT 文の最大バイト数です. 0 のときは制限しません.
+	// MySQL では max_allowed_packet より小さい値を指定します.
+	MaxBytes int
+
+	// Tx が true のとき, すべてのチャンクを 1 つのトランザクションで実行します.
+	// このとき conn は *sql.DB や HookedConn のように BeginTx メソッドを持つ必要があります.
+	Tx bool
+
+	// Excludes は INSERT しないカラムです. auto オプションを持つカラムは常に除外します.
+	Excludes []string
+}
+
+// BatchError は InsertBatches で INSERT に失敗したチャンクを表すエラーです.
+type BatchError struct {
+	Chunk  int // 失敗したチャンクの番号
+	Offset int // チャンクの先頭行の位置
+	Rows   int // チャンクの行数
+	Err    error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("batch chunk %d (rows %d-%d): %v", e.Chunk, e.Offset, e.Offset+e.Rows-1, e.Err)
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+var errRowTooLarge = errors.New("row exceeds max bytes")
+
+// InsertBatches は rows を opts に従って複数の INSERT 文に分割して実行し, 影響を受けた行数の合計を返します.
+//
+// 各 INSERT 文は行を展開しながらバイト数を測り, MaxRows, MaxBytes を超えないように分割します.
+// 失敗したときは *BatchError を返します. Tx が true のときはロールバックし, 行数は 0 になります.
+// 各 INSERT 文は Exec と同じく AddHook で登録した Hook を呼び出します.
+func InsertBatches[V any](conn sqlt.Execer, ctx context.Context, table string, rows []V, opts BatchOptions) (int64, error) {
+	if len(rows) == 0 {
+		return 0, errEmptySlice
+	}
+
+	if opts.Tx {
+		tx, err := beginTx(ctx, conn, nil)
+		if err != nil {
+			return 0, err
+		}
+		total, err := insertBatches(tx, ctx, table, rows, opts)
+		if err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+		return total, tx.Commit()
+	}
+	return insertBatches(conn, ctx, table, rows, opts)
+}
+
+func insertBatches[V any](conn sqlt.Execer, ctx context.Context, table string, rows []V, opts BatchOptions) (int64, error) {
+	infos := getColumnInfos((*V)(nil))
+	excludes := append(autoColumnNames(infos), opts.Excludes...)
+	cols := excludeColumns(infos, excludes)
+
+	w := &strings.Builder{}
+	if err := writeInsertHeader(w, table, Columns((*V)(nil), excludes...)); err != nil {
+		return 0, err
+	}
+	header := w.String()
+
+	var (
+		total int64
+		chunk int
+		begin int
+		row   = &strings.Builder{}
+		vals  = make([]any, len(cols))
+	)
+	flush := func(end int) error {
+		res, err := execHooked(ctx, globalHooks(), conn, w.String(), nil)
+		if err == nil {
+			var n int64
+			n, err = res.RowsAffected()
+			total += n
+		}
+		if err != nil {
+			return &BatchError{chunk, begin, end - begin, err}
+		}
+		w.Reset()
+		w.WriteString(header)
+		chunk, begin = chunk+1, end
+		return nil
+	}
+
+	for i := range rows {
+		row.Reset()
+		rv := reflect.ValueOf(rows[i])
+		for j, c := range cols {
+			vals[j] = c.value(rv)
+		}
+		row.WriteByte('(')
+		if err := putValueList(row, vals); err != nil {
+			return total, &BatchError{chunk, i, 1, err}
+		}
+		row.WriteByte(')')
+
+		if opts.MaxBytes > 0 && len(header)+row.Len() > opts.MaxBytes {
+			return total, &BatchError{chunk, i, 1, errRowTooLarge}
+		}
+		if i > begin {
+			full := opts.MaxRows > 0 && i-begin >= opts.MaxRows ||
+				opts.MaxBytes > 0 && w.Len()+2+row.Len() > opts.MaxBytes
+			if full {
+				if err := flush(i); err != nil {
+					return total, err
+				}
+			} else {
+				w.WriteString(", ")
+			}
+		}
+		w.WriteString(row.String())
+	}
+	if err := flush(len(rows)); err != nil {
+		return total, err
+	}
+	return total, nil
+}
+
+// writeInsertHeader は INSERT INTO table (columns) VALUES を展開します.
+func writeInsertHeader(w Writer, table string, columns []string) error {
+	w.WriteString("INSERT INTO ")
+	if err := writeIdent(w, table); err != nil {
+		return err
+	}
+	w.WriteString(" (")
+	if err := putIdentList(w, columns); err != nil {
+		return err
+	}
+	w.WriteString(") VALUES ")
+	return nil
+}
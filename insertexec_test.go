@@ -0,0 +1,308 @@
+package sqlb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	d "github.com/17e10/go-sqlb/dialect"
+	"github.com/17e10/go-sqlb/sqlt"
+)
+
+type autoPerson struct {
+	Id   uint64 `sqlb:"id,pk,auto"`
+	Name string
+}
+
+type testResult struct {
+	lastInsertId int64
+}
+
+func (r testResult) LastInsertId() (int64, error) {
+	return r.lastInsertId, nil
+}
+
+func (r testResult) RowsAffected() (int64, error) {
+	return 0, nil
+}
+
+type testQueryExecer struct {
+	queries []string
+	result  sql.Result
+}
+
+func (e *testQueryExecer) ExecContext(_ context.Context, query string, _ ...any) (sql.Result, error) {
+	e.queries = append(e.queries, query)
+	return e.result, nil
+}
+
+func (e *testQueryExecer) QueryContext(_ context.Context, query string, _ ...any) (*sql.Rows, error) {
+	e.queries = append(e.queries, query)
+	return nil, errors.New("query not supported")
+}
+
+func TestReturning(t *testing.T) {
+	mysqlDialect := dialect()
+	defer d.SetDialect(mysqlDialect)
+
+	tests := []struct {
+		sqler Sqler
+		want  string
+		err   string
+	}{
+		{Insert("person").Columns("name").Values("a").Returning("id"),
+			"INSERT INTO `person` (`name`) VALUES ('a') RETURNING `id`", ""},
+		{Update("person").Set(Kv{"name", "a"}).Where(T("id = 1")).Returning(),
+			"UPDATE `person` SET `name` = 'a' WHERE id = 1 RETURNING *", ""},
+		{Delete("person").Where(T("id = 1")).Returning("id", "name"),
+			"DELETE FROM `person` WHERE id = 1 RETURNING `id`, `name`", ""},
+	}
+	outputs := []string{
+		"INSERT INTO `person` (`name`) OUTPUT INSERTED.`id` VALUES ('a')",
+		"UPDATE `person` SET `name` = 'a' OUTPUT INSERTED.* WHERE id = 1",
+		"DELETE FROM `person` OUTPUT DELETED.`id`, DELETED.`name` WHERE id = 1",
+	}
+
+	for i, te := range tests {
+		name := fmt.Sprintf("test Returning #%d", i)
+
+		_, err := Stringify(te.sqler)
+		if !errors.Is(err, errNoReturning) {
+			t.Errorf("%s errored %v, want %v", name, err, errNoReturning)
+		}
+
+		d.SetDialect(pgDialect{mysqlDialect})
+		got, err := Stringify(te.sqler)
+		if err != nil || got != te.want {
+			t.Errorf("%s = %q, %v, want %q", name, got, err, te.want)
+		}
+
+		d.SetDialect(msDialect{mysqlDialect})
+		got, err = Stringify(te.sqler)
+		if err != nil || got != outputs[i] {
+			t.Errorf("%s = %q, %v, want %q", name, got, err, outputs[i])
+		}
+
+		d.SetDialect(mysqlDialect)
+	}
+}
+
+func TestInsertReturningLastInsertId(t *testing.T) {
+	rows := []autoPerson{{Name: "Olivia"}, {Name: "Kenny"}}
+	conn := &testQueryExecer{result: testResult{10}}
+
+	if err := InsertReturning(conn, context.TODO(), "person", rows); err != nil {
+		t.Fatalf("%s errored %v", "test InsertReturning", err)
+	}
+	want := "INSERT INTO `person` (`name`) VALUES ('Olivia'), ('Kenny')"
+	if len(conn.queries) != 1 || conn.queries[0] != want {
+		t.Errorf("%s queries %q, want %q", "test InsertReturning", conn.queries, want)
+	}
+	if rows[0].Id != 10 || rows[1].Id != 11 {
+		t.Errorf("%s ids %d, %d, want 10, 11", "test InsertReturning", rows[0].Id, rows[1].Id)
+	}
+}
+
+func TestInsertReturningQuery(t *testing.T) {
+	useDialect(t, pgDialect{dialect()})
+
+	rows := []autoPerson{{Name: "Olivia"}}
+	conn := &testQueryExecer{}
+
+	err := InsertReturning(conn, context.TODO(), "person", rows)
+	want := "INSERT INTO `person` (`name`) VALUES ('Olivia') RETURNING `id`"
+	if err == nil || len(conn.queries) != 1 || conn.queries[0] != want {
+		t.Errorf("%s queries %q, want %q", "test InsertReturning", conn.queries, want)
+	}
+}
+
+func TestInsertReturningScan(t *testing.T) {
+	useDialect(t, pgDialect{dialect()})
+
+	rows := []autoPerson{{Name: "Olivia"}, {Name: "Kenny"}}
+	conn := &sqlt.TestConn{Rows: sqlt.TestRows{
+		Columns: []string{"id", "name"},
+		Values:  [][]any{{int64(10), "OLIVIA"}, {int64(11), "KENNY"}},
+	}}
+
+	if err := InsertReturning(conn, context.TODO(), "person", rows, "id", "name"); err != nil {
+		t.Fatalf("%s errored %v", "test InsertReturning", err)
+	}
+	want := "INSERT INTO `person` (`name`) VALUES ('Olivia'), ('Kenny') RETURNING `id`, `name`"
+	if len(conn.Queried) != 1 || conn.Queried[0].Query != want {
+		t.Errorf("%s queried %v, want %q", "test InsertReturning", conn.Queried, want)
+	}
+	wantRows := []autoPerson{{10, "OLIVIA"}, {11, "KENNY"}}
+	if rows[0] != wantRows[0] || rows[1] != wantRows[1] {
+		t.Errorf("%s rows %v, want %v", "test InsertReturning", rows, wantRows)
+	}
+}
+
+func TestInsertReturningShort(t *testing.T) {
+	useDialect(t, pgDialect{dialect()})
+
+	rows := []autoPerson{{Name: "Olivia"}, {Name: "Kenny"}}
+	conn := &sqlt.TestConn{Rows: sqlt.TestRows{
+		Columns: []string{"id"},
+		Values:  [][]any{{int64(10)}},
+	}}
+
+	err := InsertReturning(conn, context.TODO(), "person", rows)
+	if !errors.Is(err, errOutRange) {
+		t.Errorf("%s errored %v, want %v", "test InsertReturning short rows", err, errOutRange)
+	}
+}
+
+func TestInsertReturningError(t *testing.T) {
+	type person struct {
+		Id   uint64 `sqlb:"id,pk"`
+		Name string
+	}
+
+	conn := &testQueryExecer{result: testResult{10}}
+	err := InsertReturning(conn, context.TODO(), "person", []person{{Name: "Olivia"}})
+	if !errors.Is(err, errEmptySlice) {
+		t.Errorf("%s errored %v, want %v", "test InsertReturning no columns", err, errEmptySlice)
+	}
+	err = InsertReturning(conn, context.TODO(), "person", []autoPerson{{Name: "Olivia"}}, "name")
+	if !errors.Is(err, errNoReturning) {
+		t.Errorf("%s errored %v, want %v", "test InsertReturning non-auto column", err, errNoReturning)
+	}
+	if len(conn.queries) != 0 {
+		t.Errorf("%s queries %q, want none", "test InsertReturning", conn.queries)
+	}
+}
+
+func TestInsertStructs(t *testing.T) {
+	tests := []struct {
+		increment int64
+		want      []uint64
+	}{
+		{0, []uint64{100, 101, 102}},
+		{1, []uint64{100, 101, 102}},
+		{2, []uint64{100, 102, 104}},
+	}
+
+	for i, te := range tests {
+		name := fmt.Sprintf("test InsertStructs #%d", i)
+		rows := []autoPerson{{Name: "a"}, {Name: "b"}, {Name: "c"}}
+		execer := &sqlt.TestExecer{LastInsertId: 100, RowsAffected: 3}
+
+		res, err := InsertStructs(execer, context.TODO(), "person", rows, te.increment)
+		if err != nil {
+			t.Fatalf("%s errored %v", name, err)
+		}
+		if n, _ := res.RowsAffected(); n != 3 {
+			t.Errorf("%s RowsAffected %d, want %d", name, n, 3)
+		}
+		want := "INSERT INTO `person` (`name`) VALUES ('a'), ('b'), ('c')"
+		if len(execer.Execed) != 1 || execer.Execed[0].Query != want {
+			t.Errorf("%s execed %v, want %q", name, execer.Execed, want)
+		}
+		for j, row := range rows {
+			if row.Id != te.want[j] {
+				t.Errorf("%s row %d id %d, want %d", name, j, row.Id, te.want[j])
+			}
+		}
+	}
+
+	_, err := InsertStructs(&sqlt.TestExecer{}, context.TODO(), "person", []autoPerson{}, 1)
+	if !errors.Is(err, errEmptySlice) {
+		t.Errorf("%s errored %v, want %v", "test InsertStructs empty", err, errEmptySlice)
+	}
+}
+
+type failExecer struct {
+	sqlt.TestExecer
+	fail int
+}
+
+func (e *failExecer) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	if len(e.Execed) == e.fail {
+		return nil, errors.New("packet too large")
+	}
+	return e.TestExecer.ExecContext(ctx, query, args...)
+}
+
+func TestInsertBatches(t *testing.T) {
+	rows := []autoPerson{{Name: "a"}, {Name: "b"}, {Name: "c"}, {Name: "d"}, {Name: "e"}}
+	const header = "INSERT INTO `person` (`name`) VALUES "
+
+	tests := []struct {
+		opts BatchOptions
+		want []string
+	}{
+		{BatchOptions{}, []string{header + "('a'), ('b'), ('c'), ('d'), ('e')"}},
+		{BatchOptions{MaxRows: 2}, []string{header + "('a'), ('b')", header + "('c'), ('d')", header + "('e')"}},
+		{BatchOptions{MaxBytes: len(header) + 15}, []string{header + "('a'), ('b')", header + "('c'), ('d')", header + "('e')"}},
+		{BatchOptions{MaxRows: 4, MaxBytes: len(header) + 24}, []string{header + "('a'), ('b'), ('c')", header + "('d'), ('e')"}},
+	}
+
+	for i, te := range tests {
+		name := fmt.Sprintf("test InsertBatches #%d", i)
+		execer := &sqlt.TestExecer{RowsAffected: 2}
+
+		total, err := InsertBatches(execer, context.TODO(), "person", rows, te.opts)
+		if err != nil {
+			t.Fatalf("%s errored %v", name, err)
+		}
+		if want := int64(2 * len(te.want)); total != want {
+			t.Errorf("%s total %d, want %d", name, total, want)
+		}
+		if len(execer.Execed) != len(te.want) {
+			t.Errorf("%s execed %d times, want %d", name, len(execer.Execed), len(te.want))
+			continue
+		}
+		for j, e := range execer.Execed {
+			if e.Query != te.want[j] {
+				t.Errorf("%s query %d = %q, want %q", name, j, e.Query, te.want[j])
+			}
+		}
+	}
+}
+
+func TestInsertBatchesHooks(t *testing.T) {
+	var log []string
+	AddHook(recordHook("global", &log, nil))
+	defer SetHooks()
+
+	rows := []autoPerson{{Name: "a"}, {Name: "b"}, {Name: "c"}}
+	if _, err := InsertBatches(&sqlt.TestExecer{}, context.TODO(), "person", rows, BatchOptions{MaxRows: 2}); err != nil {
+		t.Fatalf("%s errored %v", "test InsertBatches hooks", err)
+	}
+	want := []string{"global before exec", "global after exec", "global before exec", "global after exec"}
+	if !reflect.DeepEqual(log, want) {
+		t.Errorf("%s log %q, want %q", "test InsertBatches hooks", log, want)
+	}
+}
+
+func TestInsertBatchesError(t *testing.T) {
+	rows := []autoPerson{{Name: "a"}, {Name: "b"}, {Name: "c"}, {Name: "d"}, {Name: "e"}}
+	execer := &failExecer{sqlt.TestExecer{RowsAffected: 2}, 1}
+
+	total, err := InsertBatches(execer, context.TODO(), "person", rows, BatchOptions{MaxRows: 2})
+	var be *BatchError
+	if !errors.As(err, &be) {
+		t.Fatalf("%s errored %v, want *BatchError", "test InsertBatches error", err)
+	}
+	if total != 2 || be.Chunk != 1 || be.Offset != 2 || be.Rows != 2 {
+		t.Errorf("%s = %d, %+v", "test InsertBatches error", total, be)
+	}
+	want := "batch chunk 1 (rows 2-3): packet too large"
+	if err.Error() != want {
+		t.Errorf("%s errored %q, want %q", "test InsertBatches error", err.Error(), want)
+	}
+
+	_, err = InsertBatches(&sqlt.TestExecer{}, context.TODO(), "person", rows, BatchOptions{MaxBytes: 10})
+	if !errors.Is(err, errRowTooLarge) {
+		t.Errorf("%s errored %v, want %v", "test InsertBatches too large", err, errRowTooLarge)
+	}
+
+	_, err = InsertBatches(&sqlt.TestExecer{}, context.TODO(), "person", rows, BatchOptions{Tx: true})
+	if !errors.Is(err, errNoBeginner) {
+		t.Errorf("%s errored %v, want %v", "test InsertBatches tx", err, errNoBeginner)
+	}
+}
@@ -0,0 +1,280 @@
+// testdriver パッケージは sqlt のテストダブルと sqltest が共有するテスト用の database/sql ドライバです.
+//
+// ドライバは操作を Handler に委ねます.
+// Handler は接続ごとに NewConn で与えるか, DB で実行するときの context に WithHandler で与えます.
+package testdriver
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// ErrNotSupported は Handler が操作を持たないときのエラーです.
+var ErrNotSupported = errors.New("testdriver: operation not supported")
+
+// Rows は Query が返す結果の行です.
+//
+// Types はカラムのデータベースの型名で, sql.ColumnType の DatabaseTypeName が返します.
+type Rows struct {
+	Columns []string
+	Types   []string
+	Values  [][]any
+}
+
+// Handler はドライバの操作の振る舞いです. nil の関数の操作は ErrNotSupported になります.
+// ただし Prepare, Commit, Rollback が nil のときは何もせずに成功します.
+type Handler struct {
+	Prepare  func(query string) error
+	Query    func(query string, args []any) (Rows, error)
+	Exec     func(query string, args []any) (driver.Result, error)
+	Begin    func() error
+	Commit   func() error
+	Rollback func() error
+}
+
+type handlerKey struct{}
+
+// WithHandler は h を持つ context を返します.
+func WithHandler(ctx context.Context, h *Handler) context.Context {
+	return context.WithValue(ctx, handlerKey{}, h)
+}
+
+var db struct {
+	once sync.Once
+	db   *sql.DB
+}
+
+// DB は context の Handler で操作を実行する *sql.DB を返します.
+func DB() *sql.DB {
+	db.once.Do(func() {
+		db.db = sql.OpenDB(connector{})
+	})
+	return db.db
+}
+
+// connector は DB の driver.Connector です.
+type connector struct{}
+
+func (connector) Connect(context.Context) (driver.Conn, error) {
+	return &conn{}, nil
+}
+
+func (connector) Driver() driver.Driver {
+	return drv{}
+}
+
+type drv struct{}
+
+func (drv) Open(string) (driver.Conn, error) {
+	return &conn{}, nil
+}
+
+// NewConn は h で操作を実行する driver.Conn を返します.
+//
+// context が Handler を持つときはそちらを使います.
+func NewConn(h *Handler) driver.Conn {
+	return &conn{h: h}
+}
+
+// conn はドライバの接続です.
+//
+// トランザクション中は BeginTx の Handler を保持し,
+// context に Handler を持たない操作に使います.
+type conn struct {
+	h  *Handler
+	tx *Handler
+}
+
+func (c *conn) handler(ctx context.Context) *Handler {
+	if h, ok := ctx.Value(handlerKey{}).(*Handler); ok {
+		return h
+	}
+	if c.tx != nil {
+		return c.tx
+	}
+	return c.h
+}
+
+func (c *conn) CheckNamedValue(*driver.NamedValue) error {
+	return nil
+}
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	h := c.handler(ctx)
+	if h == nil {
+		return nil, ErrNotSupported
+	}
+	if h.Prepare != nil {
+		if err := h.Prepare(query); err != nil {
+			return nil, err
+		}
+	}
+	return &stmt{h, query}, nil
+}
+
+func (c *conn) Close() error {
+	return nil
+}
+
+func (c *conn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *conn) BeginTx(ctx context.Context, _ driver.TxOptions) (driver.Tx, error) {
+	h := c.handler(ctx)
+	if h == nil || h.Begin == nil {
+		return nil, ErrNotSupported
+	}
+	if err := h.Begin(); err != nil {
+		return nil, err
+	}
+	c.tx = h
+	return tx{c}, nil
+}
+
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return doQuery(c.handler(ctx), query, args)
+}
+
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return doExec(c.handler(ctx), query, args)
+}
+
+func doQuery(h *Handler, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if h == nil || h.Query == nil {
+		return nil, ErrNotSupported
+	}
+	r, err := h.Query(query, namedValues(args))
+	if err != nil {
+		return nil, err
+	}
+	return &rows{rows: r}, nil
+}
+
+func doExec(h *Handler, query string, args []driver.NamedValue) (driver.Result, error) {
+	if h == nil || h.Exec == nil {
+		return nil, ErrNotSupported
+	}
+	return h.Exec(query, namedValues(args))
+}
+
+func namedValues(args []driver.NamedValue) []any {
+	if len(args) == 0 {
+		return nil
+	}
+	r := make([]any, len(args))
+	for i, a := range args {
+		r[i] = a.Value
+	}
+	return r
+}
+
+// tx はドライバのトランザクションです.
+type tx struct {
+	c *conn
+}
+
+func (t tx) Commit() error {
+	h := t.c.tx
+	t.c.tx = nil
+	if h.Commit == nil {
+		return nil
+	}
+	return h.Commit()
+}
+
+func (t tx) Rollback() error {
+	h := t.c.tx
+	t.c.tx = nil
+	if h.Rollback == nil {
+		return nil
+	}
+	return h.Rollback()
+}
+
+// stmt はドライバのプリペアドステートメントです.
+type stmt struct {
+	h     *Handler
+	query string
+}
+
+func (s *stmt) Close() error {
+	return nil
+}
+
+func (s *stmt) NumInput() int {
+	return -1
+}
+
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), toNamedValues(args))
+}
+
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), toNamedValues(args))
+}
+
+func (s *stmt) ExecContext(_ context.Context, args []driver.NamedValue) (driver.Result, error) {
+	return doExec(s.h, s.query, args)
+}
+
+func (s *stmt) QueryContext(_ context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return doQuery(s.h, s.query, args)
+}
+
+func toNamedValues(args []driver.Value) []driver.NamedValue {
+	r := make([]driver.NamedValue, len(args))
+	for i, v := range args {
+		r[i] = driver.NamedValue{Ordinal: i + 1, Value: v}
+	}
+	return r
+}
+
+// rows は Rows を返す driver.Rows です.
+type rows struct {
+	rows Rows
+	i    int
+}
+
+func (r *rows) Columns() []string {
+	return r.rows.Columns
+}
+
+func (r *rows) ColumnTypeDatabaseTypeName(index int) string {
+	if index < len(r.rows.Types) {
+		return r.rows.Types[index]
+	}
+	return ""
+}
+
+func (r *rows) Close() error {
+	return nil
+}
+
+func (r *rows) Next(dest []driver.Value) error {
+	if r.i >= len(r.rows.Values) {
+		return io.EOF
+	}
+	row := r.rows.Values[r.i]
+	r.i++
+	if len(row) != len(dest) {
+		return fmt.Errorf("testdriver: row %d has %d values, want %d", r.i-1, len(row), len(dest))
+	}
+	for i, v := range row {
+		dv, err := driver.DefaultParameterConverter.ConvertValue(v)
+		if err != nil {
+			return fmt.Errorf("testdriver: row %d column %d: %w", r.i-1, i, err)
+		}
+		dest[i] = dv
+	}
+	return nil
+}
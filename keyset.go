@@ -0,0 +1,184 @@
+package sqlb
+
+import (
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+var (
+	errInvalidCursor = errors.New("invalid cursor")
+	errNullCursor    = errors.New("null cursor value")
+)
+
+// Seek はキーセットページネーションの条件式を作成します.
+//
+// Seek は keys の順に並べたとき, last の値を持つ行より後ろにある行を選びます.
+// すべてのキーが同じ方向で dialect が行値の比較をサポートするときは行値で比較します.
+//
+//	(`created_at`, `id`) < ('2001-01-02 13:14:15', 100)
+//
+// そうでないときは OR で展開します.
+//
+//	(`created_at` < '2001-01-02 13:14:15' OR (`created_at` = '2001-01-02 13:14:15' AND `id` > 100))
+//
+// last に NULL を含めることはできません. NULL を含むときはエラーを返します.
+func Seek(keys []SortKey, last []any) Sqler {
+	fn := func(w Writer) error {
+		if err := seek(w, keys, last); err != nil {
+			return fmt.Errorf("seek: %w", err)
+		}
+		return nil
+	}
+	return sqlerFunc(fn)
+}
+
+func seek(w Writer, keys []SortKey, last []any) error {
+	if len(keys) == 0 {
+		return errEmptySlice
+	}
+	if len(keys) != len(last) {
+		return fmt.Errorf("%d keys, %d values: %w", len(keys), len(last), errOutRange)
+	}
+	for i, v := range last {
+		if isNull(v) {
+			return fmt.Errorf("%s: %w", keys[i].Column, errNullCursor)
+		}
+	}
+
+	if len(keys) == 1 {
+		return compare(keys[0].Column, seekOp(keys[0]), last[0]).Sql(w)
+	}
+	if sameDirection(keys) && dialect().RowValues() {
+		cols := make([]string, len(keys))
+		for i, k := range keys {
+			cols[i] = k.Column
+		}
+		w.WriteByte('(')
+		if err := putIdentList(w, cols); err != nil {
+			return err
+		}
+		w.WriteString(")" + seekOp(keys[0]) + "(")
+		if err := putValueList(w, last); err != nil {
+			return err
+		}
+		w.WriteByte(')')
+		return nil
+	}
+
+	ors := make([]Sqler, len(keys))
+	for i := range keys {
+		ands := make([]Sqler, i+1)
+		for j := 0; j < i; j++ {
+			ands[j] = Eq(keys[j].Column, last[j])
+		}
+		ands[i] = compare(keys[i].Column, seekOp(keys[i]), last[i])
+		ors[i] = And(ands...)
+	}
+	return Bracket(Or(ors...)).Sql(w)
+}
+
+// seekOp はキーの方向に応じた比較演算子を返します.
+func seekOp(k SortKey) string {
+	if k.Desc {
+		return " < "
+	}
+	return " > "
+}
+
+// sameDirection はすべてのキーが同じ方向か調べます.
+func sameDirection(keys []SortKey) bool {
+	for _, k := range keys[1:] {
+		if k.Desc != keys[0].Desc {
+			return false
+		}
+	}
+	return true
+}
+
+// EncodeCursor は Seek に渡す値を不透明なカーソル文字列にします.
+//
+// values が受け取れるのは bool, int, float, string などの基底型や []byte, time.Time, nil です.
+// DecodeCursor で復元すると整数は int64 または uint64 に, 浮動小数点は float64 になります.
+func EncodeCursor(values []any) (string, error) {
+	enc := make([][2]string, len(values))
+	for i, v := range values {
+		if valuer, ok := v.(driver.Valuer); ok {
+			var err error
+			if v, err = valuer.Value(); err != nil {
+				return "", fmt.Errorf("%T.Value() failed: %w", valuer, err)
+			}
+		}
+		switch x := v.(type) {
+		case nil:
+			enc[i] = [2]string{"n", ""}
+		case bool:
+			enc[i] = [2]string{"?", strconv.FormatBool(x)}
+		case string:
+			enc[i] = [2]string{"s", x}
+		case []byte:
+			enc[i] = [2]string{"b", base64.StdEncoding.EncodeToString(x)}
+		case time.Time:
+			enc[i] = [2]string{"t", x.Format(time.RFC3339Nano)}
+		case int, int8, int16, int32, int64:
+			enc[i] = [2]string{"i", strconv.FormatInt(reflect.ValueOf(v).Int(), 10)}
+		case uint, uint8, uint16, uint32, uint64:
+			enc[i] = [2]string{"u", strconv.FormatUint(reflect.ValueOf(v).Uint(), 10)}
+		case float32, float64:
+			enc[i] = [2]string{"f", strconv.FormatFloat(reflect.ValueOf(v).Float(), 'g', -1, 64)}
+		default:
+			return "", fmt.Errorf("cursor: got type %T: %w", v, errNoValueType)
+		}
+	}
+	b, err := json.Marshal(enc)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// DecodeCursor は EncodeCursor で作成したカーソル文字列から値を復元します.
+func DecodeCursor(s string) ([]any, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("cursor: %w", errInvalidCursor)
+	}
+	var enc [][2]string
+	if err = json.Unmarshal(b, &enc); err != nil {
+		return nil, fmt.Errorf("cursor: %w", errInvalidCursor)
+	}
+
+	values := make([]any, len(enc))
+	for i, e := range enc {
+		var v any
+		switch e[0] {
+		case "n":
+		case "?":
+			v, err = strconv.ParseBool(e[1])
+		case "s":
+			v = e[1]
+		case "b":
+			v, err = base64.StdEncoding.DecodeString(e[1])
+		case "t":
+			v, err = time.Parse(time.RFC3339Nano, e[1])
+		case "i":
+			v, err = strconv.ParseInt(e[1], 10, 64)
+		case "u":
+			v, err = strconv.ParseUint(e[1], 10, 64)
+		case "f":
+			v, err = strconv.ParseFloat(e[1], 64)
+		default:
+			err = errInvalidCursor
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cursor: index %d: %w", i, errInvalidCursor)
+		}
+		values[i] = v
+	}
+	return values, nil
+}
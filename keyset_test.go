@@ -0,0 +1,89 @@
+package sqlb
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	d "github.com/17e10/go-sqlb/dialect"
+)
+
+func TestSeek(t *testing.T) {
+	asc := []SortKey{{Column: "created_at"}, {Column: "id"}}
+	desc := []SortKey{{Column: "created_at", Desc: true}, {Column: "id", Desc: true}}
+	mixed := []SortKey{{Column: "created_at", Desc: true}, {Column: "name"}, {Column: "id"}}
+
+	tests := []struct {
+		dialect d.Dialect
+		keys    []SortKey
+		last    []any
+		want    string
+		err     string
+	}{
+		{nil, asc[1:], []any{100}, "`id` > 100", ""},
+		{nil, asc, []any{"2001-01-02", 100}, "(`created_at`, `id`) > ('2001-01-02', 100)", ""},
+		{nil, desc, []any{"2001-01-02", 100}, "(`created_at`, `id`) < ('2001-01-02', 100)", ""},
+		{nil, mixed, []any{"2001-01-02", "a", 100},
+			"(`created_at` < '2001-01-02' OR (`created_at` = '2001-01-02' AND `name` > 'a') OR " +
+				"(`created_at` = '2001-01-02' AND `name` = 'a' AND `id` > 100))", ""},
+		{msDialect{dialect()}, asc, []any{"2001-01-02", 100},
+			"(`created_at` > '2001-01-02' OR (`created_at` = '2001-01-02' AND `id` > 100))", ""},
+		{nil, asc, []any{100}, "", "seek: 2 keys, 1 values: out of range"},
+		{nil, nil, nil, "", "seek: empty array or slice"},
+		{nil, asc, []any{nil, 100}, "", "seek: created_at: null cursor value"},
+		{nil, asc[1:], []any{sql.NullInt64{}}, "", "seek: id: null cursor value"},
+	}
+
+	old := d.GetDialect()
+	defer d.SetDialect(old)
+
+	for i, te := range tests {
+		var goterr string
+
+		if te.dialect != nil {
+			d.SetDialect(te.dialect)
+		} else {
+			d.SetDialect(old)
+		}
+		name := fmt.Sprintf("test Seek #%d", i)
+		got, err := Stringify(Seek(te.keys, te.last))
+		if err != nil {
+			goterr = err.Error()
+		}
+		if goterr != te.err {
+			t.Errorf("%s errored %q, want %q", name, goterr, te.err)
+		}
+		if got != te.want {
+			t.Errorf("%s returned %q, want %q", name, got, te.want)
+		}
+	}
+}
+
+func TestCursor(t *testing.T) {
+	tm := time.Date(2001, time.January, 2, 13, 14, 15, 678901000, time.UTC)
+	src := []any{nil, true, "a'b", []byte{1, 2}, tm, 123, uint64(18446744073709551615), 1.5, testValuer("v")}
+	want := []any{nil, true, "a'b", []byte{1, 2}, tm, int64(123), uint64(18446744073709551615), 1.5, "v"}
+
+	s, err := EncodeCursor(src)
+	if err != nil {
+		t.Fatalf("%s errored %v", "test EncodeCursor", err)
+	}
+	got, err := DecodeCursor(s)
+	if err != nil {
+		t.Fatalf("%s errored %v", "test DecodeCursor", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("%s = %#v, want %#v", "test Cursor", got, want)
+	}
+
+	if _, err = EncodeCursor([]any{&kenny}); err == nil {
+		t.Errorf("%s no error", "test EncodeCursor invalid")
+	}
+	for _, s := range []string{"!!", "bm90IGpzb24", "W1sieCIsIiJdXQ"} {
+		if _, err = DecodeCursor(s); err == nil {
+			t.Errorf("DecodeCursor(%q) no error", s)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package sqlb
+
+import "strings"
+
+// LikePattern は LIKE のパターンを表す値です.
+//
+// @ に展開するとワイルドカード % と _ をエスケープしたパターンに
+// dialect に応じた ESCAPE 句を付けて展開します.
+//
+//	T("name LIKE @", Contains("50%"))	name LIKE '%50\\%%' ESCAPE '\\'
+//	Like("name", StartsWith("a_b"))		`name` LIKE 'a\\_b%' ESCAPE '\\'
+type LikePattern struct {
+	Prefix string // エスケープせずに先頭に付ける文字列
+	Text   string // エスケープする文字列
+	Suffix string // エスケープせずに末尾に付ける文字列
+}
+
+// Contains は s を含む文字列に一致するパターンを作成します.
+func Contains(s string) LikePattern {
+	return LikePattern{"%", s, "%"}
+}
+
+// StartsWith は s で始まる文字列に一致するパターンを作成します.
+func StartsWith(s string) LikePattern {
+	return LikePattern{"", s, "%"}
+}
+
+// EndsWith は s で終わる文字列に一致するパターンを作成します.
+func EndsWith(s string) LikePattern {
+	return LikePattern{"%", s, ""}
+}
+
+// EscapeLike は s の %, _ とエスケープ文字 esc をエスケープします.
+func EscapeLike(s string, esc byte) string {
+	var b strings.Builder
+	b.Grow(len(s) + 8)
+	for i, l := 0, len(s); i < l; i++ {
+		c := s[i]
+		if c == '%' || c == '_' || c == esc {
+			b.WriteByte(esc)
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// putLike は LIKE のパターンと ESCAPE 句を展開します.
+//
+// StringifyRedacted ではパターンを伏せたマーカーに置き換えます.
+func putLike(w Writer, p LikePattern, sensitive bool) error {
+	d := dialect()
+	esc := d.LikeEscape()
+	pattern := p.Prefix + EscapeLike(p.Text, esc) + p.Suffix
+	var err error
+	if redacts(w, sensitive) {
+		err = writeRedacted(w, pattern)
+	} else {
+		err = d.WriteString(w, pattern)
+	}
+	if err != nil {
+		return err
+	}
+	w.WriteString(" ESCAPE ")
+	return d.WriteString(w, string([]byte{esc}))
+}
@@ -0,0 +1,46 @@
+package sqlb
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestEscapeLike(t *testing.T) {
+	tests := []struct {
+		src  string
+		esc  byte
+		want string
+	}{
+		{"abc", '\\', "abc"},
+		{"50%", '\\', `50\%`},
+		{`a_b\c`, '\\', `a\_b\\c`},
+		{"50%!", '!', "50!%!!"},
+	}
+
+	for _, te := range tests {
+		got := EscapeLike(te.src, te.esc)
+		if got != te.want {
+			t.Errorf("EscapeLike(%q, %q) = %q, want %q", te.src, te.esc, got, te.want)
+		}
+	}
+}
+
+func TestLikePattern(t *testing.T) {
+	tests := []struct {
+		sqler Sqler
+		want  string
+	}{
+		{T("name LIKE @", Contains("50%")), `name LIKE '%50\\%%' ESCAPE '\\'`},
+		{T("name LIKE @", StartsWith("a_b")), `name LIKE 'a\\_b%' ESCAPE '\\'`},
+		{T("name LIKE @", EndsWith(`c:\`)), `name LIKE '%c:\\\\' ESCAPE '\\'`},
+		{Like("name", Contains("o'k")), "`name` LIKE '%o\\'k%' ESCAPE '\\\\'"},
+	}
+
+	for i, te := range tests {
+		name := fmt.Sprintf("test LikePattern #%d", i)
+		got, err := Stringify(te.sqler)
+		if err != nil || got != te.want {
+			t.Errorf("%s = %q, %v, want %q", name, got, err, te.want)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package sqlb
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SortKey はソートするカラムと方向を表します.
+type SortKey struct {
+	Column string
+	Desc   bool
+	Nulls  NullsOrder
+}
+
+// NullsOrder は ORDER BY で NULL を並べる位置を表します.
+type NullsOrder int
+
+const (
+	NullsDefault NullsOrder = iota // dialect の既定の位置
+	NullsFirst                     // NULL を先頭に並べる
+	NullsLast                      // NULL を末尾に並べる
+)
+
+// UnknownSortFieldError は ParseSort で許可されていないフィールドを指定したときのエラーです.
+type UnknownSortFieldError struct {
+	Field string
+}
+
+func (e *UnknownSortFieldError) Error() string {
+	return fmt.Sprintf("unknown sort field %q", e.Field)
+}
+
+// ParseSort はクライアントから受け取った "-created_at,name" 形式のソート指定を SortKey に変換します.
+//
+// 各フィールドの先頭に - を付けると降順に, + を付けるか何も付けないと昇順になります.
+// allow は API のフィールド名からカラム名と NULL の位置への対応です.
+// allow にないフィールドを指定すると *UnknownSortFieldError を返します.
+//
+//	keys, err := sqlb.ParseSort(r.FormValue("sort"), map[string]sqlb.SortKey{
+//		"created_at": {Column: "created_at", Nulls: sqlb.NullsLast},
+//		"name":       {Column: "name"},
+//	})
+func ParseSort(s string, allow map[string]SortKey) ([]SortKey, error) {
+	var keys []SortKey
+	for _, f := range strings.Split(s, ",") {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		desc := false
+		switch f[0] {
+		case '-':
+			desc, f = true, f[1:]
+		case '+':
+			f = f[1:]
+		}
+		key, ok := allow[f]
+		if !ok {
+			return nil, &UnknownSortFieldError{f}
+		}
+		key.Desc = desc
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// OrderBy は ORDER BY 句の項目リストを表す Sqler を作成します.
+//
+// NULLS FIRST, NULLS LAST をサポートしない dialect では `col` IS NULL で並べ替えて同じ順序にします.
+//
+//	`created_at` DESC NULLS LAST, `name`
+//	`created_at` IS NULL, `created_at` DESC, `name`	(MySQL)
+func OrderBy(keys ...SortKey) Sqler {
+	fn := func(w Writer) error {
+		if err := putOrderBy(w, keys); err != nil {
+			return fmt.Errorf("order by: %w", err)
+		}
+		return nil
+	}
+	return sqlerFunc(fn)
+}
+
+func putOrderBy(w Writer, keys []SortKey) error {
+	if len(keys) == 0 {
+		return errEmptySlice
+	}
+	nulls := dialect().NullsOrdering()
+	for i, k := range keys {
+		if i > 0 {
+			w.WriteString(", ")
+		}
+		if !nulls && k.Nulls != NullsDefault {
+			if err := writeIdent(w, k.Column); err != nil {
+				return err
+			}
+			w.WriteString(" IS NULL")
+			if k.Nulls == NullsFirst {
+				w.WriteString(" DESC")
+			}
+			w.WriteString(", ")
+		}
+		if err := writeIdent(w, k.Column); err != nil {
+			return err
+		}
+		if k.Desc {
+			w.WriteString(" DESC")
+		}
+		if nulls {
+			switch k.Nulls {
+			case NullsFirst:
+				w.WriteString(" NULLS FIRST")
+			case NullsLast:
+				w.WriteString(" NULLS LAST")
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package sqlb
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	d "github.com/17e10/go-sqlb/dialect"
+)
+
+var sortAllow = map[string]SortKey{
+	"created_at": {Column: "p.created_at", Nulls: NullsLast},
+	"name":       {Column: "p.name"},
+}
+
+func TestParseSort(t *testing.T) {
+	tests := []struct {
+		src  string
+		want []SortKey
+		err  string
+	}{
+		{"", nil, ""},
+		{"name", []SortKey{{Column: "p.name"}}, ""},
+		{"-created_at, +name", []SortKey{{"p.created_at", true, NullsLast}, {Column: "p.name"}}, ""},
+		{"name,-password", nil, `unknown sort field "password"`},
+	}
+
+	for _, te := range tests {
+		var goterr string
+
+		name := fmt.Sprintf("ParseSort(%q)", te.src)
+		got, err := ParseSort(te.src, sortAllow)
+		if err != nil {
+			goterr = err.Error()
+		}
+		if goterr != te.err {
+			t.Errorf("%s errored %q, want %q", name, goterr, te.err)
+		}
+		if !reflect.DeepEqual(got, te.want) {
+			t.Errorf("%s returned %v, want %v", name, got, te.want)
+		}
+	}
+
+	_, err := ParseSort("-id", sortAllow)
+	var ue *UnknownSortFieldError
+	if !errors.As(err, &ue) || ue.Field != "id" {
+		t.Errorf("%s errored %v, want *UnknownSortFieldError", "test ParseSort error", err)
+	}
+}
+
+func TestOrderBy(t *testing.T) {
+	keys := []SortKey{
+		{Column: "a", Desc: true, Nulls: NullsLast},
+		{Column: "b", Nulls: NullsFirst},
+		{Column: "c"},
+	}
+
+	tests := []struct {
+		dialect d.Dialect
+		keys    []SortKey
+		want    string
+		err     string
+	}{
+		{nil, keys, "`a` IS NULL, `a` DESC, `b` IS NULL DESC, `b`, `c`", ""},
+		{pgDialect{dialect()}, keys, "`a` DESC NULLS LAST, `b` NULLS FIRST, `c`", ""},
+		{nil, nil, "", "order by: empty array or slice"},
+	}
+
+	old := d.GetDialect()
+	defer d.SetDialect(old)
+
+	for i, te := range tests {
+		var goterr string
+
+		if te.dialect != nil {
+			d.SetDialect(te.dialect)
+		} else {
+			d.SetDialect(old)
+		}
+		name := fmt.Sprintf("test OrderBy #%d", i)
+		got, err := Stringify(OrderBy(te.keys...))
+		if err != nil {
+			goterr = err.Error()
+		}
+		if goterr != te.err {
+			t.Errorf("%s errored %q, want %q", name, goterr, te.err)
+		}
+		if got != te.want {
+			t.Errorf("%s returned %q, want %q", name, got, te.want)
+		}
+	}
+
+	d.SetDialect(old)
+	got, _ := Stringify(Select().From("person").OrderBy(OrderBy(keys[2:]...)))
+	if want := "SELECT * FROM `person` ORDER BY `c`"; got != want {
+		t.Errorf("%s = %q, want %q", "test OrderBy select", got, want)
+	}
+}
@@ -1,7 +1,9 @@
 package sqlb
 
 import (
+	"database/sql/driver"
 	"fmt"
+	"reflect"
 	"strconv"
 	"strings"
 
@@ -55,11 +57,19 @@ func putValue(w Writer, v any) (err error) {
 		err = putGroupList(w, val)
 	case []Kv:
 		err = putKvList(w, val)
+	case LikePattern:
+		err = putLike(w, val, false)
+	case SensitiveValue:
+		if p, ok := val.v.(LikePattern); ok {
+			err = putLike(w, p, true)
+		} else {
+			err = writeValue(w, v)
+		}
 	default:
 		err = writeValue(w, v)
 	}
 	if err != nil {
-		return fmt.Errorf("value: %v: %w", v, err)
+		return fmt.Errorf("value: %v: %w", redactedArg(w, v), err)
 	}
 	return nil
 }
@@ -127,14 +137,53 @@ func putSqler(w Writer, v any) error {
 	if !ok {
 		return fmt.Errorf("got type %T, want Sqler: %w", v, errNoValueType)
 	}
-	return sqler.Sql(w)
+	return writeSqler(w, sqler)
+}
+
+// putOperand は比較の被演算子 v を展開します.
+//
+// v が Sqler ならば展開し, そうでなければ LikePattern または 1 つの値として展開します.
+// []any, [][]any, []Kv のようなリストは被演算子にできないのでエラーにします.
+func putOperand(w Writer, v any) error {
+	switch x := v.(type) {
+	case Sqler:
+		return writeSqler(w, x)
+	case []any, [][]any, []Kv:
+		return fmt.Errorf("value: got type %T: %w", v, errNoValueType)
+	}
+	return putValue(w, v)
+}
+
+// putIdentOrSqler は v が Sqler ならば展開し, そうでなければ識別子として展開します.
+func putIdentOrSqler(w Writer, v any) error {
+	if sqler, ok := v.(Sqler); ok {
+		return writeSqler(w, sqler)
+	}
+	return putIdent(w, v)
+}
+
+// putIdentOrSqlerList は識別子または Sqler のリストを , で区切って展開します.
+func putIdentOrSqlerList(w Writer, v []any) error {
+	if len(v) == 0 {
+		return errEmptySlice
+	}
+	for i, val := range v {
+		if i > 0 {
+			w.WriteString(", ")
+		}
+		if err := putIdentOrSqler(w, val); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // putEqValue は擬似イコール構文を含めた値を展開します.
 func putEqValue(w Writer, eq string, v any) error {
-	switch val := v.(type) {
-	case nil:
+	if isNull(v) {
 		return putIsNull(w, eq)
+	}
+	switch val := v.(type) {
 	case []any:
 		switch len(val) {
 		case 0:
@@ -147,6 +196,26 @@ func putEqValue(w Writer, eq string, v any) error {
 	return putEqual(w, eq, v)
 }
 
+// isNull は v が NULL として展開される値か調べます.
+//
+// Sensitive を外し, driver.Valuer は Value の結果で判定します.
+// sql.NullString{} のように Valid でない値も NULL です.
+func isNull(v any) bool {
+	if s, ok := v.(SensitiveValue); ok {
+		v = s.v
+	}
+	if valuer, ok := v.(driver.Valuer); ok {
+		if rv := reflect.ValueOf(valuer); rv.Kind() == reflect.Pointer && rv.IsNil() {
+			return true
+		}
+		var err error
+		if v, err = valuer.Value(); err != nil {
+			return false
+		}
+	}
+	return v == nil
+}
+
 // putIsNull は擬似イコール構文を IS (NOT) NULL に展開します.
 func putIsNull(w Writer, eq string) error {
 	if eq == "==" {
@@ -0,0 +1,113 @@
+package sqlb
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// SensitiveValue は SQL のログなどで伏せる値を表します.
+//
+// 通常の展開では値をそのまま展開し,
+// StringifyRedacted では '<string:12>' のような型と長さだけのマーカーに置き換えます.
+type SensitiveValue struct {
+	v any
+}
+
+// Sensitive は v を伏せる値として包みます.
+func Sensitive(v any) SensitiveValue {
+	return SensitiveValue{v}
+}
+
+// String は包んだ値を伏せた文字列を返します.
+//
+// エラーメッセージやログに値が漏れないようにします.
+func (s SensitiveValue) String() string {
+	return "<sensitive>"
+}
+
+// GoString は String と同じく包んだ値を伏せた文字列を返します.
+func (s SensitiveValue) GoString() string {
+	return s.String()
+}
+
+// Value は driver.Valuer を実装します.
+//
+// Values などの結果を database/sql の引数として渡せるように, 包んだ値を変換して返します.
+func (s SensitiveValue) Value() (driver.Value, error) {
+	return driver.DefaultParameterConverter.ConvertValue(s.v)
+}
+
+// RedactMode は StringifyRedacted が伏せる値の範囲を表します.
+type RedactMode int
+
+const (
+	// RedactSensitive は Sensitive で包んだ値と sensitive オプションを持つフィールドの値を伏せます.
+	RedactSensitive RedactMode = iota
+
+	// RedactAll はすべての値を伏せます.
+	RedactAll
+)
+
+// redactWriter は値を伏せて展開する Writer です.
+//
+// writeValue は Writer が redactWriter のとき値をマーカーに置き換えます.
+type redactWriter struct {
+	Writer
+	mode RedactMode
+}
+
+// StringifyRedacted は値を伏せて Sqler から文字列を得ます.
+//
+// 伏せた値は '<string:12>', '<int>', '<time>' のように型と長さだけを表すマーカーになります.
+// NULL と識別子, SQL の構造はそのまま展開します.
+func StringifyRedacted(sqler Sqler, mode RedactMode) (string, error) {
+	const cap = 256
+
+	b := &strings.Builder{}
+	b.Grow(cap)
+	if err := writeSqler(&redactWriter{b, mode}, sqler); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// redacts は値 v を伏せるかを返します.
+func redacts(w Writer, sensitive bool) bool {
+	rw, ok := w.(*redactWriter)
+	return ok && (sensitive || rw.mode == RedactAll)
+}
+
+// redactedArg はエラーメッセージに含める値 v を返します.
+//
+// 値を伏せて展開しているときは型だけにします.
+func redactedArg(w Writer, v any) any {
+	if _, ok := v.(SensitiveValue); ok || redacts(w, false) {
+		return fmt.Sprintf("%T", v)
+	}
+	return v
+}
+
+// writeRedacted は値 v を伏せたマーカーを書き込みます.
+func writeRedacted(w Writer, v any) error {
+	var marker string
+	switch x := v.(type) {
+	case string:
+		marker = fmt.Sprintf("<string:%d>", utf8.RuneCountInString(x))
+	case []byte:
+		marker = fmt.Sprintf("<bytes:%d>", len(x))
+	case bool:
+		marker = "<bool>"
+	case time.Time:
+		marker = "<time>"
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		marker = "<int>"
+	case float32, float64:
+		marker = "<float>"
+	default:
+		return fmt.Errorf("got type %T: %w", v, errNoValueType)
+	}
+	return dialect().WriteString(w, marker)
+}
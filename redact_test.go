@@ -0,0 +1,128 @@
+package sqlb
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStringifyRedacted(t *testing.T) {
+	type user struct {
+		ID    int64
+		Email string `sqlb:"email,sensitive"`
+		Name  string
+	}
+	u := user{1, "foo@example.com", "たろう"}
+	at := time.Date(2001, time.January, 2, 13, 14, 15, 0, time.UTC)
+
+	tests := []struct {
+		src  Sqler
+		mode RedactMode
+		want string
+	}{
+		{
+			T("SELECT * FROM #0 WHERE #1 == @2 AND #3 == @4", "t", "email", Sensitive("foo@example.com"), "name", "bar"),
+			RedactSensitive,
+			"SELECT * FROM `t` WHERE `email` = '<string:15>' AND `name` = 'bar'",
+		},
+		{
+			T("SELECT * FROM #0 WHERE #1 == @2 AND #3 == @4", "t", "email", Sensitive("foo@example.com"), "name", "bar"),
+			RedactAll,
+			"SELECT * FROM `t` WHERE `email` = '<string:15>' AND `name` = '<string:3>'",
+		},
+		{
+			T("@, @, @, @, @, @, @", 1, uint8(2), 1.5, true, at, []byte("abc"), nil),
+			RedactAll,
+			"'<int>', '<int>', '<float>', '<bool>', '<time>', '<bytes:3>', NULL",
+		},
+		{
+			T("token == @", Sensitive(nil)),
+			RedactSensitive,
+			"token IS NULL",
+		},
+		{
+			Insert("users").Struct(&u),
+			RedactSensitive,
+			"INSERT INTO `users` (`id`, `email`, `name`) VALUES (1, '<string:15>', 'たろう')",
+		},
+		{
+			Insert("users").Struct(&u),
+			RedactAll,
+			"INSERT INTO `users` (`id`, `email`, `name`) VALUES ('<int>', '<string:15>', '<string:3>')",
+		},
+		{
+			T("UPDATE users SET @", []Kv{{"token", Sensitive("secret")}}),
+			RedactSensitive,
+			"UPDATE users SET `token` = '<string:6>'",
+		},
+		{
+			T("name LIKE @", Contains("50%")),
+			RedactAll,
+			"name LIKE '<string:6>' ESCAPE '\\\\'",
+		},
+		{
+			Like("name", Sensitive(StartsWith("secret"))),
+			RedactSensitive,
+			"`name` LIKE '<string:7>' ESCAPE '\\\\'",
+		},
+		{
+			T("name LIKE @ AND note LIKE @", Sensitive(Contains("a")), EndsWith("b")),
+			RedactSensitive,
+			"name LIKE '<string:3>' ESCAPE '\\\\' AND note LIKE '%b' ESCAPE '\\\\'",
+		},
+	}
+
+	for _, te := range tests {
+		got, err := StringifyRedacted(te.src, te.mode)
+		if err != nil {
+			t.Errorf("StringifyRedacted(%d) errored %v", te.mode, err)
+			continue
+		}
+		if got != te.want {
+			t.Errorf("StringifyRedacted(%d) = %q, want %q", te.mode, got, te.want)
+		}
+	}
+}
+
+func TestSensitive(t *testing.T) {
+	got, err := Stringify(T("@ @ @", Sensitive("foo"), Sensitive(1), Sensitive(Contains("a"))))
+	want := "'foo' 1 '%a%' ESCAPE '\\\\'"
+	if err != nil || got != want {
+		t.Errorf("%s = %q, %v, want %q", "test Sensitive", got, err, want)
+	}
+
+	v, err := Sensitive(1).Value()
+	if err != nil || v != driver.Value(int64(1)) {
+		t.Errorf("%s Value() = %v, %v, want %v", "test Sensitive", v, err, 1)
+	}
+}
+
+func TestSensitiveError(t *testing.T) {
+	type secret struct{ Token string }
+
+	tests := []struct {
+		src  Sqler
+		mode RedactMode
+	}{
+		{T("token == @", Sensitive(secret{"xyzzy"})), RedactSensitive},
+		{T("token = @", Sensitive(secret{"xyzzy"})), RedactSensitive},
+		{T("token = @", secret{"xyzzy"}), RedactAll},
+	}
+
+	for i, te := range tests {
+		_, err := StringifyRedacted(te.src, te.mode)
+		if err == nil {
+			t.Errorf("test SensitiveError #%d returned no error", i)
+			continue
+		}
+		if strings.Contains(err.Error(), "xyzzy") {
+			t.Errorf("test SensitiveError #%d errored %q, leaks value", i, err)
+		}
+	}
+
+	if s := fmt.Sprintf("%v %+v %#v", Sensitive("xyzzy"), Sensitive("xyzzy"), Sensitive("xyzzy")); strings.Contains(s, "xyzzy") {
+		t.Errorf("formatted Sensitive = %q, leaks value", s)
+	}
+}
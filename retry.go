@@ -0,0 +1,122 @@
+package sqlb
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// sqlStater は SQLSTATE を返すドライバのエラーです.
+type sqlStater interface {
+	SQLState() string
+}
+
+// IsRetryable はデッドロックやシリアライゼーション失敗など
+// トランザクションを再試行すれば成功しうるエラーか判定します.
+//
+// dialect の判定に加えて, SQLState メソッドを持つエラーは
+// SQLSTATE 40001 (serialization_failure), 40P01 (deadlock_detected) を再試行可能とします.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	var se sqlStater
+	if errors.As(err, &se) {
+		if s := se.SQLState(); s == "40001" || s == "40P01" {
+			return true
+		}
+	}
+	return dialect().IsRetryable(err)
+}
+
+// RetryPolicy は再試行可能なエラーを再試行する方針です.
+//
+// 待ち時間は試行ごとに 2 倍に増え, その範囲でランダムに揺らぎます.
+//
+//	p := sqlb.RetryPolicy{MaxAttempts: 5}
+//	err := p.Do(ctx, func(ctx context.Context) error {
+//		return sqlb.InTx(ctx, db, nil, func(tx sqlt.Conn) error {
+//			...
+//		})
+//	})
+type RetryPolicy struct {
+	// MaxAttempts は最大試行回数です. 0 以下のときは 3 回です.
+	MaxAttempts int
+
+	// BaseDelay は最初の再試行までの最大待ち時間です. 0 以下のときは 10ms です.
+	BaseDelay time.Duration
+
+	// MaxDelay は待ち時間の上限です. 0 以下のときは 1s です.
+	MaxDelay time.Duration
+
+	// Retryable は再試行可能なエラーか判定します. nil のときは IsRetryable を使います.
+	Retryable func(err error) bool
+
+	// OnRetry は再試行する前に失敗した試行回数とエラー, 待ち時間を受け取ります.
+	OnRetry func(attempt int, err error, delay time.Duration)
+}
+
+// Do は fn が成功するか再試行できないエラーを返すまで fn を繰り返し呼び出します.
+//
+// 最大試行回数に達したときは最後のエラーを返します.
+// 待っている間に ctx が終了したときは ctx.Err() を返します.
+func (p RetryPolicy) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	maxAttempts, base, maxDelay := p.MaxAttempts, p.BaseDelay, p.MaxDelay
+	if maxAttempts <= 0 {
+		maxAttempts = 3
+	}
+	if base <= 0 {
+		base = 10 * time.Millisecond
+	}
+	if maxDelay <= 0 {
+		maxDelay = time.Second
+	}
+	retryable := p.Retryable
+	if retryable == nil {
+		retryable = IsRetryable
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil || attempt >= maxAttempts || !retryable(err) {
+			return err
+		}
+
+		n := int64(backoffCeil(base, maxDelay, attempt))
+		if n < math.MaxInt64 {
+			n++
+		}
+		delay := time.Duration(rand.Int63n(n))
+		if p.OnRetry != nil {
+			p.OnRetry(attempt, err, delay)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// backoffCeil は attempt 回目の失敗の後の待ち時間の上限を返します.
+//
+// 上限は base から失敗ごとに 2 倍になり, maxDelay で頭打ちになります.
+// 試行回数が多くてもオーバーフローしないように maxDelay を超える前に倍増を止めます.
+func backoffCeil(base, maxDelay time.Duration, attempt int) time.Duration {
+	ceil := base
+	for i := 1; i < attempt; i++ {
+		if ceil > maxDelay/2 {
+			return maxDelay
+		}
+		ceil *= 2
+	}
+	if ceil > maxDelay {
+		return maxDelay
+	}
+	return ceil
+}
@@ -0,0 +1,150 @@
+package sqlb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+)
+
+var errDeadlock = errors.New("deadlock")
+
+func isDeadlock(err error) bool {
+	return errors.Is(err, errDeadlock)
+}
+
+type sqlStateError string
+
+func (e sqlStateError) Error() string {
+	return "sqlstate " + string(e)
+}
+
+func (e sqlStateError) SQLState() string {
+	return string(e)
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		src  error
+		want bool
+	}{
+		{nil, false},
+		{errDeadlock, false},
+		{sqlStateError("40001"), true},
+		{fmt.Errorf("commit: %w", sqlStateError("40P01")), true},
+		{sqlStateError("23505"), false},
+	}
+
+	for _, te := range tests {
+		got := IsRetryable(te.src)
+		if got != te.want {
+			t.Errorf("IsRetryable(%v) = %v, want %v", te.src, got, te.want)
+		}
+	}
+}
+
+func TestRetryPolicy(t *testing.T) {
+	ctx := context.TODO()
+	errFatal := errors.New("fatal")
+
+	tests := []struct {
+		results  []error
+		attempts int
+		err      error
+	}{
+		{[]error{nil}, 1, nil},
+		{[]error{errDeadlock, errDeadlock, nil}, 3, nil},
+		{[]error{errDeadlock, errFatal, nil}, 2, errFatal},
+		{[]error{errDeadlock, errDeadlock, errDeadlock, nil}, 3, errDeadlock},
+	}
+
+	for i, te := range tests {
+		var (
+			calls   int
+			retries []int
+		)
+		p := RetryPolicy{
+			BaseDelay: time.Microsecond,
+			Retryable: isDeadlock,
+			OnRetry: func(attempt int, err error, delay time.Duration) {
+				retries = append(retries, attempt)
+				if delay < 0 || delay > 2*time.Microsecond {
+					t.Errorf("test RetryPolicy #%d delay %v", i, delay)
+				}
+			},
+		}
+		err := p.Do(ctx, func(context.Context) error {
+			calls++
+			return te.results[calls-1]
+		})
+		if err != te.err {
+			t.Errorf("test RetryPolicy #%d errored %v, want %v", i, err, te.err)
+		}
+		if calls != te.attempts || len(retries) != te.attempts-1 {
+			t.Errorf("test RetryPolicy #%d calls %d, retries %v, want %d", i, calls, retries, te.attempts)
+		}
+	}
+}
+
+func TestBackoffCeil(t *testing.T) {
+	const maxDuration = time.Duration(math.MaxInt64)
+
+	tests := []struct {
+		base, maxDelay time.Duration
+		attempt        int
+		want           time.Duration
+	}{
+		{10 * time.Millisecond, time.Second, 1, 10 * time.Millisecond},
+		{10 * time.Millisecond, time.Second, 3, 40 * time.Millisecond},
+		{10 * time.Millisecond, time.Second, 8, time.Second},
+		{10 * time.Millisecond, time.Second, 100, time.Second},
+		{time.Hour, maxDuration, 64, maxDuration},
+		{time.Hour, maxDuration, 1000, maxDuration},
+		{time.Minute, time.Second, 1, time.Second},
+	}
+
+	for _, te := range tests {
+		got := backoffCeil(te.base, te.maxDelay, te.attempt)
+		if got != te.want {
+			t.Errorf("backoffCeil(%v, %v, %d) = %v, want %v", te.base, te.maxDelay, te.attempt, got, te.want)
+		}
+	}
+}
+
+func TestRetryPolicyCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := RetryPolicy{
+		MaxAttempts: 10,
+		BaseDelay:   time.Hour,
+		MaxDelay:    time.Hour,
+		Retryable:   isDeadlock,
+		OnRetry:     func(int, error, time.Duration) { cancel() },
+	}
+
+	calls := 0
+	err := p.Do(ctx, func(context.Context) error {
+		calls++
+		return errDeadlock
+	})
+	if err != context.Canceled || calls != 1 {
+		t.Errorf("%s = %v, %d calls, want %v", "test RetryPolicy cancel", err, calls, context.Canceled)
+	}
+}
+
+func TestRetryPolicyMaxDuration(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := RetryPolicy{
+		MaxAttempts: 100,
+		BaseDelay:   time.Duration(math.MaxInt64),
+		MaxDelay:    time.Duration(math.MaxInt64),
+		Retryable:   isDeadlock,
+		OnRetry:     func(int, error, time.Duration) { cancel() },
+	}
+
+	err := p.Do(ctx, func(context.Context) error { return errDeadlock })
+	if err != context.Canceled {
+		t.Errorf("%s = %v, want %v", "test RetryPolicy max duration", err, context.Canceled)
+	}
+}
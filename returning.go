@@ -0,0 +1,57 @@
+package sqlb
+
+import (
+	"errors"
+
+	d "github.com/17e10/go-sqlb/dialect"
+)
+
+var errNoReturning = errors.New("returning not supported")
+
+// putOutput は dialect が OUTPUT 句を使うとき OUTPUT prefix.col, ... を展開します.
+//
+// returning が nil のときは何も展開しません. 空のときは prefix.* を展開します.
+func putOutput(w Writer, returning []string, prefix string) error {
+	if returning == nil || dialect().ReturningStyle() != d.ReturningOutput {
+		return nil
+	}
+	w.WriteString(" OUTPUT ")
+	return putReturningList(w, returning, prefix)
+}
+
+// putReturning は dialect が RETURNING 句を使うとき RETURNING col, ... を展開します.
+//
+// returning が nil のときは何も展開しません. 空のときは * を展開します.
+// dialect が変更した行を返す構文を持たないときはエラーを返します.
+func putReturning(w Writer, returning []string) error {
+	if returning == nil {
+		return nil
+	}
+	switch dialect().ReturningStyle() {
+	case d.ReturningClause:
+		w.WriteString(" RETURNING ")
+		return putReturningList(w, returning, "")
+	case d.ReturningOutput:
+		return nil
+	}
+	return errNoReturning
+}
+
+// putReturningList は prefix を付けた識別子リストを展開します.
+func putReturningList(w Writer, returning []string, prefix string) error {
+	if len(returning) == 0 {
+		w.WriteString(prefix)
+		w.WriteByte('*')
+		return nil
+	}
+	for i, s := range returning {
+		if i > 0 {
+			w.WriteString(", ")
+		}
+		w.WriteString(prefix)
+		if err := writeIdent(w, s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
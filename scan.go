@@ -1,7 +1,10 @@
 package sqlb
 
 import (
+	"database/sql"
 	"reflect"
+	"strconv"
+	"strings"
 
 	"github.com/17e10/go-sqlb/sqlt"
 )
@@ -9,18 +12,145 @@ import (
 // Scan は database/sql の Row(s).Scan メソッドの結果を構造体に入れます.
 //
 // Scan で受け取るフィールド順序は Columns で生成されるカラム列の順序と一致します.
+//
+// nullzero オプションを持つフィールドは NULL を受け取るとゼロ値になります.
+// ポインタ型のフィールドは NULL を受け取ると nil になります.
+//
+//	type person struct {
+//		Nickname string `sqlb:"nickname,nullzero"`
+//	}
 func Scan[V any](row sqlt.RowsScanner, dest *V) error {
 	d := makeFieldsAddr(dest)
-	return row.Scan(d...)
+	if err := row.Scan(d...); err != nil {
+		return err
+	}
+	storeNullFields(dest, d)
+	return nil
 }
 
 // makeFieldsAddr は構造体から各要素のポインタ配列を作成します.
+//
+// nullzero オプションを持つポインタ型以外のフィールドは
+// NULL を受け取れるように中間のポインタを作成します.
 func makeFieldsAddr[V any](v *V) []any {
 	cols := exportedColumns(v, nil)
 	rv := reflect.ValueOf(v).Elem()
 	r := make([]any, len(cols))
 	for i, c := range cols {
-		r[i] = rv.FieldByIndex(c.index).Addr().Interface()
+		fv := rv.FieldByIndex(c.index)
+		if c.nullzero && fv.Kind() != reflect.Pointer {
+			r[i] = reflect.New(reflect.PointerTo(fv.Type())).Interface()
+		} else {
+			r[i] = fv.Addr().Interface()
+		}
 	}
 	return r
 }
+
+// storeNullFields は makeFieldsAddr が作成した中間のポインタから構造体に値を格納します.
+func storeNullFields[V any](v *V, d []any) {
+	cols := exportedColumns(v, nil)
+	rv := reflect.ValueOf(v).Elem()
+	for i, c := range cols {
+		fv := rv.FieldByIndex(c.index)
+		if !c.nullzero || fv.Kind() == reflect.Pointer {
+			continue
+		}
+		p := reflect.ValueOf(d[i]).Elem()
+		if p.IsNil() {
+			fv.SetZero()
+		} else {
+			fv.Set(p.Elem())
+		}
+	}
+}
+
+// ScanValue は 1 カラムの結果を V 型の値として読み込みます.
+//
+// COUNT(*) や id の取得など 1 カラムだけの結果を読み込むときに使います.
+func ScanValue[V any](row sqlt.RowsScanner) (V, error) {
+	var v V
+	err := row.Scan(&v)
+	return v, err
+}
+
+// ScanMap は現在の行をカラム名をキーとする map に読み込みます.
+//
+// 値はカラムの型に応じて正規化されます. 詳しくは ScanSlice を参照してください.
+func ScanMap(rows sqlt.ColumnsScanner) (map[string]any, error) {
+	types, vals, err := scanAny(rows)
+	if err != nil {
+		return nil, err
+	}
+	r := make(map[string]any, len(vals))
+	for i, ct := range types {
+		r[ct.Name()] = vals[i]
+	}
+	return r, nil
+}
+
+// ScanSlice は現在の行を []any に読み込みます.
+//
+// ドライバが []byte で返した値はカラムの型に応じて正規化されます.
+// 文字列型は string に, 整数型は int64 (または uint64) に, 浮動小数点型は float64 に変換し,
+// バイナリ型と型の分からないカラムは []byte のままにします.
+func ScanSlice(rows sqlt.ColumnsScanner) ([]any, error) {
+	_, vals, err := scanAny(rows)
+	return vals, err
+}
+
+// scanAny は現在の行を any として読み込み, カラムの型に応じて正規化します.
+func scanAny(rows sqlt.ColumnsScanner) ([]*sql.ColumnType, []any, error) {
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, nil, err
+	}
+	vals := make([]any, len(types))
+	d := make([]any, len(types))
+	for i := range vals {
+		d[i] = &vals[i]
+	}
+	if err = rows.Scan(d...); err != nil {
+		return nil, nil, err
+	}
+	for i, ct := range types {
+		vals[i] = normalizeValue(vals[i], ct.DatabaseTypeName())
+	}
+	return types, vals, nil
+}
+
+// intTypes は整数に正規化するデータベースの型名です.
+var intTypes = map[string]bool{
+	"TINYINT": true, "SMALLINT": true, "MEDIUMINT": true, "INT": true, "INTEGER": true, "BIGINT": true,
+	"UNSIGNED TINYINT": true, "UNSIGNED SMALLINT": true, "UNSIGNED MEDIUMINT": true,
+	"UNSIGNED INT": true, "UNSIGNED INTEGER": true, "UNSIGNED BIGINT": true,
+	"INT2": true, "INT4": true, "INT8": true, "YEAR": true,
+}
+
+// normalizeValue はドライバが返した値をデータベースの型名 dbType に応じて正規化します.
+func normalizeValue(v any, dbType string) any {
+	b, ok := v.([]byte)
+	if !ok || dbType == "" {
+		return v
+	}
+
+	dbType = strings.ToUpper(dbType)
+	switch {
+	case strings.Contains(dbType, "BINARY"), strings.Contains(dbType, "BLOB"),
+		dbType == "BYTEA", dbType == "BIT", dbType == "GEOMETRY":
+		return b
+	case intTypes[dbType]:
+		if i, err := strconv.ParseInt(string(b), 10, 64); err == nil {
+			return i
+		}
+		if u, err := strconv.ParseUint(string(b), 10, 64); err == nil {
+			return u
+		}
+	case dbType == "FLOAT", dbType == "DOUBLE", dbType == "REAL",
+		strings.HasPrefix(dbType, "FLOAT"), strings.HasPrefix(dbType, "DOUBLE"):
+		if f, err := strconv.ParseFloat(string(b), 64); err == nil {
+			return f
+		}
+	}
+	return string(b)
+}
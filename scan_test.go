@@ -1,7 +1,12 @@
 package sqlb
 
 import (
+	"context"
+	"fmt"
+	"reflect"
 	"testing"
+
+	"github.com/17e10/go-sqlb/sqlt"
 )
 
 func equalPtrs(t *testing.T, name string, x, y []any) {
@@ -44,6 +49,125 @@ func TestMakeFieldsAddr(t *testing.T) {
 	equalPtrs(t, "test makeFieldsAddr", result, want)
 }
 
+type testScanner []any
+
+func (s testScanner) Scan(dest ...any) error {
+	if len(dest) != len(s) {
+		return fmt.Errorf("got %d dest, want %d", len(dest), len(s))
+	}
+	for i, d := range dest {
+		dv := reflect.ValueOf(d).Elem()
+		switch sv := reflect.ValueOf(s[i]); {
+		case s[i] == nil:
+			dv.SetZero()
+		case dv.Kind() == reflect.Pointer && dv.Type() != sv.Type():
+			p := reflect.New(dv.Type().Elem())
+			p.Elem().Set(sv)
+			dv.Set(p)
+		default:
+			dv.Set(sv)
+		}
+	}
+	return nil
+}
+
+func TestScanNullzero(t *testing.T) {
+	type data struct {
+		Id       int64
+		Nickname string  `sqlb:"nickname,nullzero"`
+		Memo     *string `sqlb:",nullzero"`
+	}
+
+	got := data{Nickname: "old"}
+	if err := Scan(testScanner{int64(1), nil, nil}, &got); err != nil {
+		t.Fatalf("%s errored %v", "test Scan nullzero #1", err)
+	}
+	want := data{Id: 1}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("%s = %+v, want %+v", "test Scan nullzero #1", got, want)
+	}
+
+	if err := Scan(testScanner{int64(2), "kenny", "memo"}, &got); err != nil {
+		t.Fatalf("%s errored %v", "test Scan nullzero #2", err)
+	}
+	if got.Id != 2 || got.Nickname != "kenny" || got.Memo == nil || *got.Memo != "memo" {
+		t.Errorf("%s = %+v", "test Scan nullzero #2", got)
+	}
+}
+
+func TestScanValue(t *testing.T) {
+	got, err := ScanValue[int64](testScanner{int64(42)})
+	if err != nil || got != 42 {
+		t.Errorf("%s = %v, %v, want %v", "test ScanValue #1", got, err, 42)
+	}
+
+	_, err = ScanValue[int64](testScanner{int64(1), int64(2)})
+	if err == nil {
+		t.Errorf("%s no error", "test ScanValue #2")
+	}
+}
+
+func TestNormalizeValue(t *testing.T) {
+	tests := []struct {
+		src    any
+		dbType string
+		want   any
+	}{
+		{[]byte("abc"), "VARCHAR", "abc"},
+		{[]byte("abc"), "text", "abc"},
+		{[]byte("abc"), "BLOB", []byte("abc")},
+		{[]byte("abc"), "VARBINARY", []byte("abc")},
+		{[]byte("abc"), "", []byte("abc")},
+		{[]byte("123"), "BIGINT", int64(123)},
+		{[]byte("18446744073709551615"), "UNSIGNED BIGINT", uint64(18446744073709551615)},
+		{[]byte("12"), "UNSIGNED TINYINT", int64(12)},
+		{[]byte("7"), "int4", int64(7)},
+		{[]byte("2001"), "YEAR", int64(2001)},
+		{[]byte("1 day"), "INTERVAL", "1 day"},
+		{[]byte("12"), "POINTS", "12"},
+		{[]byte("1.5"), "DOUBLE", 1.5},
+		{[]byte("1.50"), "DECIMAL", "1.50"},
+		{int64(123), "BIGINT", int64(123)},
+		{nil, "VARCHAR", nil},
+	}
+
+	for _, te := range tests {
+		name := fmt.Sprintf("normalizeValue(%v, %q)", te.src, te.dbType)
+		got := normalizeValue(te.src, te.dbType)
+		if !reflect.DeepEqual(got, te.want) {
+			t.Errorf("%s = %#v, want %#v", name, got, te.want)
+		}
+	}
+}
+
+func TestScanMap(t *testing.T) {
+	q := &sqlt.TestQueryer{Rows: sqlt.TestRows{
+		Columns: []string{"id", "name", "data", "score", "note"},
+		Types:   []string{"BIGINT", "VARCHAR", "BLOB", "DOUBLE", "TEXT"},
+		Values:  [][]any{{[]byte("1"), []byte("Olivia"), []byte{0, 1}, []byte("1.5"), nil}},
+	}}
+
+	rows, err := q.QueryContext(context.TODO(), "SELECT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		t.Fatalf("%s no rows", "test ScanMap")
+	}
+
+	gotSlice, err := ScanSlice(rows)
+	wantSlice := []any{int64(1), "Olivia", []byte{0, 1}, 1.5, nil}
+	if err != nil || !reflect.DeepEqual(gotSlice, wantSlice) {
+		t.Errorf("%s = %#v, %v, want %#v", "test ScanSlice", gotSlice, err, wantSlice)
+	}
+	gotMap, err := ScanMap(rows)
+	wantMap := map[string]any{"id": int64(1), "name": "Olivia", "data": []byte{0, 1}, "score": 1.5, "note": nil}
+	if err != nil || !reflect.DeepEqual(gotMap, wantMap) {
+		t.Errorf("%s = %#v, %v, want %#v", "test ScanMap", gotMap, err, wantMap)
+	}
+}
+
 func BenchmarkMakeFieldsAddr(b *testing.B) {
 	dest := struct {
 		FirstName string
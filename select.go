@@ -0,0 +1,237 @@
+package sqlb
+
+import (
+	"errors"
+	"fmt"
+)
+
+var errNoJoinOn = errors.New("no ON condition")
+
+// SelectBuilder は SELECT 文を組み立てる Sqler です.
+//
+// SelectBuilder は不変です. 各メソッドは変更を加えた新しい SelectBuilder を返すので
+// 途中までの SelectBuilder を共有して別のクエリを組み立てられます.
+//
+// カラムやテーブルには識別子を表す string, []string または Sqler を指定できます.
+//
+//	q := sqlb.Select("id", "given_name").
+//		From("person").
+//		Where(sqlb.T("age >= @", 20)).
+//		OrderBy("id").
+//		Limit(10)
+type SelectBuilder struct {
+	distinct  bool
+	columns   []any
+	from      any
+	joins     []selectJoin
+	where     []Sqler
+	groupBy   []any
+	having    []Sqler
+	orderBy   []any
+	limit     int64
+	offset    int64
+	forUpdate bool
+}
+
+// selectJoin は JOIN 句を表します.
+type selectJoin struct {
+	kind  string
+	table any
+	on    Sqler
+}
+
+// Select は columns を取得する SelectBuilder を作成します.
+//
+// columns を省略すると * を取得します.
+func Select(columns ...any) *SelectBuilder {
+	return &SelectBuilder{columns: columns, limit: -1, offset: -1}
+}
+
+// appendClone は s を共有しないように複製してから v を追加します.
+func appendClone[T any](s []T, v ...T) []T {
+	return append(s[:len(s):len(s)], v...)
+}
+
+// Distinct は SELECT DISTINCT にします.
+func (b *SelectBuilder) Distinct() *SelectBuilder {
+	c := *b
+	c.distinct = true
+	return &c
+}
+
+// From は FROM 句のテーブルを指定します.
+func (b *SelectBuilder) From(table any) *SelectBuilder {
+	c := *b
+	c.from = table
+	return &c
+}
+
+// Join は INNER JOIN を追加します.
+func (b *SelectBuilder) Join(table any, on Sqler) *SelectBuilder {
+	return b.join("JOIN", table, on)
+}
+
+// LeftJoin は LEFT JOIN を追加します.
+//
+// on が nil のときはエラーになります.
+func (b *SelectBuilder) LeftJoin(table any, on Sqler) *SelectBuilder {
+	return b.join("LEFT JOIN", table, on)
+}
+
+func (b *SelectBuilder) join(kind string, table any, on Sqler) *SelectBuilder {
+	c := *b
+	c.joins = appendClone(c.joins, selectJoin{kind, table, on})
+	return &c
+}
+
+// Where は WHERE 句の条件を追加します.
+//
+// 複数の条件は AND で繋げます.
+func (b *SelectBuilder) Where(conds ...Sqler) *SelectBuilder {
+	c := *b
+	c.where = appendClone(c.where, conds...)
+	return &c
+}
+
+// GroupBy は GROUP BY 句のカラムを追加します.
+func (b *SelectBuilder) GroupBy(columns ...any) *SelectBuilder {
+	c := *b
+	c.groupBy = appendClone(c.groupBy, columns...)
+	return &c
+}
+
+// Having は HAVING 句の条件を追加します.
+//
+// 複数の条件は AND で繋げます.
+func (b *SelectBuilder) Having(conds ...Sqler) *SelectBuilder {
+	c := *b
+	c.having = appendClone(c.having, conds...)
+	return &c
+}
+
+// OrderBy は ORDER BY 句のカラムを追加します.
+//
+// 降順にするには Desc を使います.
+func (b *SelectBuilder) OrderBy(columns ...any) *SelectBuilder {
+	c := *b
+	c.orderBy = appendClone(c.orderBy, columns...)
+	return &c
+}
+
+// Limit は取得する最大行数を指定します.
+func (b *SelectBuilder) Limit(n int64) *SelectBuilder {
+	c := *b
+	c.limit = n
+	return &c
+}
+
+// Offset は読み飛ばす行数を指定します.
+//
+// Limit を指定しないときの展開は dialect によります.
+// MySQL では LIMIT に最大値を指定します.
+func (b *SelectBuilder) Offset(n int64) *SelectBuilder {
+	c := *b
+	c.offset = n
+	return &c
+}
+
+// ForUpdate は FOR UPDATE を付けます.
+func (b *SelectBuilder) ForUpdate() *SelectBuilder {
+	c := *b
+	c.forUpdate = true
+	return &c
+}
+
+// Sql は SELECT 文を展開します.
+func (b *SelectBuilder) Sql(w Writer) error {
+	if err := b.sql(w); err != nil {
+		return fmt.Errorf("select: %w", err)
+	}
+	return nil
+}
+
+func (b *SelectBuilder) sql(w Writer) error {
+	w.WriteString("SELECT ")
+	if b.distinct {
+		w.WriteString("DISTINCT ")
+	}
+	if len(b.columns) == 0 {
+		w.WriteByte('*')
+	} else if err := putIdentOrSqlerList(w, b.columns); err != nil {
+		return err
+	}
+	if b.from != nil {
+		w.WriteString(" FROM ")
+		if err := putIdentOrSqler(w, b.from); err != nil {
+			return err
+		}
+	}
+	for _, j := range b.joins {
+		w.WriteByte(' ')
+		w.WriteString(j.kind)
+		w.WriteByte(' ')
+		if err := putIdentOrSqler(w, j.table); err != nil {
+			return err
+		}
+		if j.on == nil {
+			if j.kind == "LEFT JOIN" {
+				return fmt.Errorf("%s: %w", j.kind, errNoJoinOn)
+			}
+			continue
+		}
+		w.WriteString(" ON ")
+		if err := writeSqler(w, j.on); err != nil {
+			return err
+		}
+	}
+	if err := putWhere(w, b.where); err != nil {
+		return err
+	}
+	if len(b.groupBy) > 0 {
+		w.WriteString(" GROUP BY ")
+		if err := putIdentOrSqlerList(w, b.groupBy); err != nil {
+			return err
+		}
+	}
+	if len(b.having) > 0 {
+		w.WriteString(" HAVING ")
+		if err := And(b.having...).Sql(w); err != nil {
+			return err
+		}
+	}
+	if err := putOrderLimit(w, b.orderBy, b.limit, b.offset); err != nil {
+		return err
+	}
+	if b.forUpdate {
+		w.WriteByte(' ')
+		return dialect().WriteForUpdate(w)
+	}
+	return nil
+}
+
+// putOrderLimit は ORDER BY, LIMIT, OFFSET 句を展開します.
+//
+// limit, offset が負のときは展開しません. LIMIT, OFFSET 句は dialect が展開します.
+func putOrderLimit(w Writer, orderBy []any, limit, offset int64) error {
+	if len(orderBy) > 0 {
+		w.WriteString(" ORDER BY ")
+		if err := putIdentOrSqlerList(w, orderBy); err != nil {
+			return err
+		}
+	}
+	if limit < 0 && offset < 0 {
+		return nil
+	}
+	w.WriteByte(' ')
+	return dialect().WriteLimit(w, limit, offset)
+}
+
+// Asc は昇順の ORDER BY 項目を表す Sqler を作成します.
+func Asc(column string) Sqler {
+	return T("# ASC", column)
+}
+
+// Desc は降順の ORDER BY 項目を表す Sqler を作成します.
+func Desc(column string) Sqler {
+	return T("# DESC", column)
+}
@@ -0,0 +1,114 @@
+package sqlb
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSelect(t *testing.T) {
+	base := Select("id", "given_name").From("person")
+
+	tests := []struct {
+		sqler Sqler
+		want  string
+		err   string
+	}{
+		{Select(), "SELECT *", ""},
+		{Select(T("COUNT(*)")).From("person"), "SELECT COUNT(*) FROM `person`", ""},
+		{base, "SELECT `id`, `given_name` FROM `person`", ""},
+		{Select([]string{"p.id", "a.city"}).Distinct().
+			From(T("# p", "person")).
+			Join(T("# a", "address"), T("a.person_id = p.id")).
+			LeftJoin("tag", T("tag.person_id = p.id")),
+			"SELECT DISTINCT `p`.`id`, `a`.`city` FROM `person` p JOIN `address` a ON a.person_id = p.id LEFT JOIN `tag` ON tag.person_id = p.id", ""},
+		{base.Join("tag", nil), "SELECT `id`, `given_name` FROM `person` JOIN `tag`", ""},
+		{base.LeftJoin("tag", nil), "", "select: LEFT JOIN: no ON condition"},
+		{base.Where(T("age >= @", 20), T("# == @", "nickname", nil)),
+			"SELECT `id`, `given_name` FROM `person` WHERE age >= 20 AND `nickname` IS NULL", ""},
+		{base.GroupBy("family_name").Having(T("COUNT(*) > @", 1)),
+			"SELECT `id`, `given_name` FROM `person` GROUP BY `family_name` HAVING COUNT(*) > 1", ""},
+		{base.OrderBy("family_name", Desc("age")).Limit(10).Offset(20).ForUpdate(),
+			"SELECT `id`, `given_name` FROM `person` ORDER BY `family_name`, `age` DESC LIMIT 10 OFFSET 20 FOR UPDATE", ""},
+		{base.Offset(20), "SELECT `id`, `given_name` FROM `person` LIMIT 18446744073709551615 OFFSET 20", ""},
+		{Select("*"), "", "select: ident: *: not allowed asterisk"},
+	}
+
+	for i, te := range tests {
+		var goterr string
+
+		name := fmt.Sprintf("test Select #%d", i)
+		got, err := Stringify(te.sqler)
+		if err != nil {
+			goterr = err.Error()
+		}
+		if goterr != te.err {
+			t.Errorf("%s errored %q, want %q", name, goterr, te.err)
+		}
+		if got != te.want {
+			t.Errorf("%s returned %q, want %q", name, got, te.want)
+		}
+	}
+}
+
+func TestSelectDialect(t *testing.T) {
+	useDialect(t, msDialect{dialect()})
+	base := Select("id").From("person").OrderBy("id")
+
+	tests := []struct {
+		sqler Sqler
+		want  string
+		err   string
+	}{
+		{base.Limit(10).Offset(20), "SELECT `id` FROM `person` ORDER BY `id` OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY", ""},
+		{base.Offset(20), "SELECT `id` FROM `person` ORDER BY `id` OFFSET 20 ROWS", ""},
+		{Union(base, base).Limit(5), "(SELECT `id` FROM `person` ORDER BY `id`) UNION (SELECT `id` FROM `person` ORDER BY `id`) OFFSET 0 ROWS FETCH NEXT 5 ROWS ONLY", ""},
+		{base.ForUpdate(), "", "select: FOR UPDATE not supported"},
+	}
+
+	for i, te := range tests {
+		var goterr string
+
+		name := fmt.Sprintf("test Select dialect #%d", i)
+		got, err := Stringify(te.sqler)
+		if err != nil {
+			goterr = err.Error()
+		}
+		if goterr != te.err {
+			t.Errorf("%s errored %q, want %q", name, goterr, te.err)
+		}
+		if got != te.want {
+			t.Errorf("%s returned %q, want %q", name, got, te.want)
+		}
+	}
+}
+
+func TestSelectImmutable(t *testing.T) {
+	base := Select().From("person").Where(T("a"))
+	x := base.Where(T("b"))
+	y := base.Where(T("c"))
+
+	for _, te := range []struct {
+		sqler Sqler
+		want  string
+	}{
+		{base, "SELECT * FROM `person` WHERE a"},
+		{x, "SELECT * FROM `person` WHERE a AND b"},
+		{y, "SELECT * FROM `person` WHERE a AND c"},
+	} {
+		got, _ := Stringify(te.sqler)
+		if got != te.want {
+			t.Errorf("%s = %q, want %q", "test Select immutable", got, te.want)
+		}
+	}
+}
+
+func TestSelectCompose(t *testing.T) {
+	sub := Select("person_id").From("address").Where(T("city = @", "Sydney"))
+	q := T("SELECT # FROM person WHERE id IN ($)", "given_name", sub)
+
+	got, err := Stringify(q)
+	want := "SELECT `given_name` FROM person WHERE id IN (SELECT `person_id` FROM `address` WHERE city = 'Sydney')"
+	if err != nil || got != want {
+		t.Errorf("%s = %q, %v, want %q", "test Select compose", got, err, want)
+	}
+}
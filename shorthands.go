@@ -8,18 +8,20 @@ import (
 )
 
 // Query は database/sql の QueryContext メソッドを Sqler で呼び出すショートハンドです.
+//
+// Query, QueryRow, Exec は AddHook で登録した Hook を呼び出します.
 func Query(conn sqlt.Queryer, ctx context.Context, sqler Sqler) (*sql.Rows, error) {
 	query, err := Stringify(sqler)
 	if err != nil {
 		return nil, err
 	}
-	return conn.QueryContext(ctx, query)
+	return queryHooked(ctx, globalHooks(), conn, query, nil)
 }
 
 // QueryRow は database/sql の QueryRowContext メソッドを Sqler で呼び出すショートハンドです.
 func QueryRow(conn sqlt.QueryRower, ctx context.Context, sqler Sqler) *sql.Row {
 	query, _ := Stringify(sqler)
-	return conn.QueryRowContext(ctx, query)
+	return queryRowHooked(ctx, globalHooks(), conn, query, nil)
 }
 
 // Exec は database/sql の ExecContext メソッドを Sqler で呼び出すショートハンドです.
@@ -28,5 +30,5 @@ func Exec(conn sqlt.Execer, ctx context.Context, sqler Sqler) (sql.Result, error
 	if err != nil {
 		return nil, err
 	}
-	return conn.ExecContext(ctx, query)
+	return execHooked(ctx, globalHooks(), conn, query, nil)
 }
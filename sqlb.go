@@ -15,6 +15,7 @@ var (
 	errOutRange    = errors.New("out of range")
 	errEmptySlice  = errors.New("empty array or slice")
 	errNoStruct    = errors.New("no struct")
+	errNoBeginner  = errors.New("conn does not support BeginTx")
 )
 
 var dialect = d.GetDialect
@@ -43,7 +44,7 @@ func Stringify(sqler Sqler) (string, error) {
 
 	w := &strings.Builder{}
 	w.Grow(cap)
-	if err := sqler.Sql(w); err != nil {
+	if err := writeSqler(w, sqler); err != nil {
 		return "", err
 	}
 	return w.String(), nil
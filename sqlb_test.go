@@ -2,8 +2,11 @@ package sqlb
 
 import (
 	"database/sql/driver"
+	"errors"
 	"fmt"
+	"testing"
 
+	d "github.com/17e10/go-sqlb/dialect"
 	_ "github.com/17e10/go-sqlb/dialect/mysql"
 	_ "github.com/go-sql-driver/mysql"
 )
@@ -36,3 +39,71 @@ type errValuer string
 func (s errValuer) Value() (driver.Value, error) {
 	return "", fmt.Errorf(string(s))
 }
+
+// pgDialect は MySQL の dialect の構文を PostgreSQL 風に差し替えたテスト用の dialect です.
+type pgDialect struct {
+	d.Dialect
+}
+
+func (pgDialect) InsertIgnore() (verb, suffix string) {
+	return "INSERT", " ON CONFLICT DO NOTHING"
+}
+
+func (pgDialect) UpsertStyle() d.UpsertStyle {
+	return d.UpsertOnConflict
+}
+
+func (pgDialect) ReturningStyle() d.ReturningStyle {
+	return d.ReturningClause
+}
+
+func (pgDialect) Materialized() bool {
+	return true
+}
+
+func (pgDialect) NullsOrdering() bool {
+	return true
+}
+
+// msDialect は MySQL の dialect の構文を SQL Server 風に差し替えたテスト用の dialect です.
+type msDialect struct {
+	d.Dialect
+}
+
+func (msDialect) UpsertStyle() d.UpsertStyle {
+	return d.UpsertMerge
+}
+
+func (msDialect) ReturningStyle() d.ReturningStyle {
+	return d.ReturningOutput
+}
+
+func (msDialect) WithRecursive() string {
+	return ""
+}
+
+func (msDialect) RowValues() bool {
+	return false
+}
+
+func (msDialect) WriteLimit(w d.Writer, limit, offset int64) error {
+	if offset < 0 {
+		offset = 0
+	}
+	fmt.Fprintf(w, "OFFSET %d ROWS", offset)
+	if limit >= 0 {
+		fmt.Fprintf(w, " FETCH NEXT %d ROWS ONLY", limit)
+	}
+	return nil
+}
+
+func (msDialect) WriteForUpdate(d.Writer) error {
+	return errors.New("FOR UPDATE not supported")
+}
+
+// useDialect はテストの間だけ dialect を di に差し替えます.
+func useDialect(t *testing.T, di d.Dialect) {
+	old := d.GetDialect()
+	d.SetDialect(di)
+	t.Cleanup(func() { d.SetDialect(old) })
+}
@@ -0,0 +1,98 @@
+// consttmpl パッケージは sqlb のテンプレートが定数かを検査するアナライザです.
+//
+// StringSqler, T, M に定数でない文字列を渡すと,
+// fmt.Sprintf などで利用者の入力を SQL に埋め込む SQL インジェクションの原因になります.
+// 値はプレースホルダ @ で展開し, どうしても必要な場合は sqlb.Raw, sqlb.Unsafe で明示します.
+// sqlb.Unsafe の引数に直接書いた呼び出しは報告しません.
+package consttmpl
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+const sqlbPath = "github.com/17e10/go-sqlb"
+
+// Analyzer は StringSqler, T, M のテンプレートが定数でない呼び出しを報告します.
+var Analyzer = &analysis.Analyzer{
+	Name:     "consttmpl",
+	Doc:      "report sqlb StringSqler, T and M calls whose template is not a constant",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	// Raw などの sqlb 自身は検査しない
+	if pass.Pkg.Path() == sqlbPath {
+		return nil, nil
+	}
+
+	ins := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	ins.WithStack([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+		call := n.(*ast.CallExpr)
+		name := templateFunc(pass.TypesInfo, call)
+		if name == "" || len(call.Args) == 0 {
+			return true
+		}
+		arg := call.Args[0]
+		if tv, ok := pass.TypesInfo.Types[arg]; ok && tv.Value != nil {
+			return true
+		}
+		if inUnsafe(pass.TypesInfo, stack) {
+			return true
+		}
+		pass.Reportf(arg.Pos(), "non-constant template passed to sqlb.%s; use placeholders, sqlb.Raw or sqlb.Unsafe", name)
+		return true
+	})
+	return nil, nil
+}
+
+// inUnsafe は stack の末尾の呼び出しが sqlb.Unsafe の引数かを返します.
+//
+// sqlb.Unsafe で包んだ Sqler は利用者が安全を確かめたものとして扱います.
+func inUnsafe(info *types.Info, stack []ast.Node) bool {
+	for i := len(stack) - 2; i >= 0; i-- {
+		switch p := stack[i].(type) {
+		case *ast.ParenExpr:
+			continue
+		case *ast.CallExpr:
+			fn, ok := typeutil.Callee(info, p).(*types.Func)
+			return ok && isSqlb(fn, "Unsafe")
+		}
+		return false
+	}
+	return false
+}
+
+// templateFunc は call が sqlb の StringSqler, T, M ならばその名前を返します.
+func templateFunc(info *types.Info, call *ast.CallExpr) string {
+	// StringSqler(s) の型変換
+	if tv, ok := info.Types[call.Fun]; ok && tv.IsType() {
+		if named, ok := tv.Type.(*types.Named); ok && isSqlb(named.Obj(), "StringSqler") {
+			return "StringSqler"
+		}
+		return ""
+	}
+
+	fn, ok := typeutil.Callee(info, call).(*types.Func)
+	if !ok {
+		return ""
+	}
+	if isSqlb(fn, "T") || isSqlb(fn, "M") {
+		return fn.Name()
+	}
+	return ""
+}
+
+// isSqlb は obj が sqlb パッケージの name かを返します.
+func isSqlb(obj types.Object, name string) bool {
+	return obj.Pkg() != nil && obj.Pkg().Path() == sqlbPath && obj.Name() == name
+}
@@ -0,0 +1,10 @@
+module github.com/17e10/go-sqlb/sqlbvet
+
+go 1.25.0
+
+require golang.org/x/tools v0.44.0
+
+require (
+	golang.org/x/mod v0.35.0 // indirect
+	golang.org/x/sync v0.21.0 // indirect
+)
@@ -0,0 +1,22 @@
+// sqlbvet は sqlb の誤った使い方を検査するコマンドです.
+//
+// 単独で実行するか go vet から利用します.
+// golang.org/x/tools に依存するため sqlb とは別のモジュールにしています.
+//
+//	go install github.com/17e10/go-sqlb/sqlbvet@latest
+//	sqlbvet ./...
+//	go vet -vettool=$(which sqlbvet) ./...
+package main
+
+import (
+	"github.com/17e10/go-sqlb/sqlbvet/consttmpl"
+	"github.com/17e10/go-sqlb/sqlbvet/tmplcheck"
+	"golang.org/x/tools/go/analysis/multichecker"
+)
+
+func main() {
+	multichecker.Main(
+		consttmpl.Analyzer,
+		tmplcheck.Analyzer,
+	)
+}
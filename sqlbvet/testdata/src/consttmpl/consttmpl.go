@@ -0,0 +1,28 @@
+package consttmpl
+
+import (
+	"fmt"
+
+	"github.com/17e10/go-sqlb"
+)
+
+const selectUser = "SELECT * FROM user WHERE id = @"
+
+func f(id string, table string) {
+	sqlb.T("SELECT * FROM user WHERE id = @", id)
+	sqlb.T(selectUser, id)
+	sqlb.T("SELECT * FROM "+"user", id)
+	sqlb.M("SELECT * FROM user WHERE id = @id", map[string]any{"@id": id})
+	_ = sqlb.StringSqler("ORDER BY id")
+	sqlb.Raw("SELECT * FROM " + table)
+	sqlb.Unsafe(sqlb.T("SELECT * FROM "+table, id))
+	sqlb.Unsafe((sqlb.StringSqler("WHERE id = " + id)))
+
+	sqlb.T("SELECT * FROM user WHERE id = " + id)                           // want `non-constant template passed to sqlb.T`
+	sqlb.T(fmt.Sprintf("SELECT * FROM %s", table))                          // want `non-constant template passed to sqlb.T`
+	sqlb.M("SELECT * FROM "+table, nil)                                     // want `non-constant template passed to sqlb.M`
+	_ = sqlb.StringSqler("SELECT * FROM user WHERE id = " + id)             // want `non-constant template passed to sqlb.StringSqler`
+	var s sqlb.Sqler = sqlb.StringSqler(fmt.Sprintf("WHERE id = '%s'", id)) // want `non-constant template passed to sqlb.StringSqler`
+	_ = s
+	sqlb.Unsafe(sqlb.Eq("id", sqlb.T("SELECT id FROM "+table))) // want `non-constant template passed to sqlb.T`
+}
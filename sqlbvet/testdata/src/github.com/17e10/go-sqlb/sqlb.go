@@ -0,0 +1,58 @@
+package sqlb
+
+import (
+	"context"
+	"database/sql"
+)
+
+type Writer interface {
+	WriteString(s string) (int, error)
+}
+
+type Sqler interface {
+	Sql(w Writer) error
+}
+
+type StringSqler string
+
+func (s StringSqler) Sql(w Writer) error {
+	w.WriteString(string(s))
+	return nil
+}
+
+func T(tmpl string, a ...any) Sqler { return nil }
+
+func M(tmpl string, params map[string]any) Sqler { return nil }
+
+func Raw(s string) Sqler { return nil }
+
+func Unsafe(sqler Sqler) Sqler { return sqler }
+
+type Kv struct {
+	K string
+	V any
+}
+
+type LikePattern struct {
+	Prefix, Text, Suffix string
+}
+
+type SensitiveValue struct {
+	v any
+}
+
+func Sensitive(v any) SensitiveValue { return SensitiveValue{v} }
+
+func Eq(column string, v any) Sqler { return nil }
+
+type RowsScanner interface {
+	Scan(dest ...any) error
+}
+
+func Columns[V any](v *V, excludes ...string) []string { return nil }
+
+func Scan[V any](row RowsScanner, dest *V) error { return nil }
+
+func Query(conn any, ctx context.Context, sqler Sqler) (*sql.Rows, error) { return nil, nil }
+
+func QueryRow(conn any, ctx context.Context, sqler Sqler) *sql.Row { return nil }
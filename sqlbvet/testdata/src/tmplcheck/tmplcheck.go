@@ -0,0 +1,120 @@
+package tmplcheck
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"time"
+
+	"github.com/17e10/go-sqlb"
+)
+
+type person struct {
+	ID   int64
+	Name string
+}
+
+type account struct {
+	ID int64
+}
+
+type age int
+
+type status int
+
+func (s status) Value() (driver.Value, error) { return int64(s), nil }
+
+type column string
+
+func placeholders(id int64, name string, cols []string, v any) {
+	sqlb.T("SELECT * FROM t WHERE id = @ AND name = @", id, name)
+	sqlb.T("SELECT # FROM t WHERE # == @", cols, "id", id)
+	sqlb.T("SELECT * FROM t WHERE a = @0 OR b = @0", id)
+	sqlb.T("SELECT * FROM t WHERE a = @1 AND b = @0", id, name)
+	sqlb.T("SELECT * FROM t WHERE $", sqlb.Eq("id", id))
+	sqlb.T("SELECT * FROM t WHERE id = @", v)
+	sqlb.T("SELECT * FROM t WHERE id = @", []any{1, 2}...)
+
+	sqlb.T("SELECT * FROM t WHERE id = @ AND name = @", id)    // want `sqlb.T placeholder "@" refers to argument 1, but 1 arguments given`
+	sqlb.T("SELECT * FROM t WHERE id = @", id, name)           // want `sqlb.T argument 1 is not used by the template`
+	sqlb.T("SELECT * FROM t WHERE id = @2", id, name)          // want `sqlb.T placeholder "@2" refers to argument 2, but 2 arguments given` `sqlb.T argument 0 is not used by the template` `sqlb.T argument 1 is not used by the template`
+	sqlb.T("SELECT * FROM t WHERE id != @", id, name)          // want `sqlb.T argument 1 is not used by the template`
+	sqlb.T("SELECT * FROM t WHERE id !== @ AND name == @", id) // want `sqlb.T placeholder "== @" refers to argument 1, but 1 arguments given`
+}
+
+func types(id int64, name string, a age, s status, at time.Time, data []byte, col column) {
+	sqlb.T("@, @, @, @, @", s, at, data, 1.5, true)
+	sqlb.T("@, @", sqlb.Sensitive(name), sqlb.LikePattern{Text: name})
+	sqlb.T("VALUES @", [][]any{{1, 2}})
+	sqlb.T("SET @", []sqlb.Kv{{"a", 1}})
+	sqlb.T("id == @", []any{1, 2})
+	sqlb.T("id == @", nil)
+
+	sqlb.T("SELECT # FROM t", 1)                              // want `sqlb "#" expects string or \[\]string, got int`
+	sqlb.T("SELECT # FROM t", col)                            // want `sqlb "#" expects string or \[\]string, got tmplcheck.column`
+	sqlb.T("SELECT * FROM t WHERE $", "id = 1")               // want `sqlb "\$" expects sqlb.Sqler, got string`
+	sqlb.T("SELECT * FROM t WHERE age = @", a)                // want `sqlb "@" cannot expand a value of type tmplcheck.age`
+	sqlb.T("SELECT * FROM t WHERE id = @", sqlb.Eq("id", id)) // want `sqlb "@" cannot expand a value of type sqlb.Sqler`
+	sqlb.T("SELECT * FROM t WHERE id = @", []int64{1, 2})     // want `sqlb "@" cannot expand a value of type \[\]int64`
+	sqlb.T("SELECT * FROM t WHERE id == @", [][]any{{1}})     // want `sqlb "== @" cannot expand a value of type \[\]\[\]any`
+}
+
+func named(id int64, params map[string]any) {
+	sqlb.M("SELECT * FROM t WHERE id = @id AND #col == @name", map[string]any{
+		"@id":   id,
+		"#col":  "name",
+		"@name": "foo",
+	})
+	sqlb.M("SELECT * FROM t WHERE id = @id", params)
+
+	sqlb.M("SELECT * FROM t WHERE id = @id", map[string]any{ // want `sqlb.M placeholder "@id" has no key "@id" in the map`
+		"id": id, // want `sqlb.M key "id" is not used by the template`
+	})
+	sqlb.M("SELECT * FROM #table", map[string]any{
+		"#table": 1, // want `sqlb "#table" expects string or \[\]string, got int`
+	})
+}
+
+func scan(conn any, ctx context.Context) {
+	var p person
+	rows, _ := sqlb.Query(conn, ctx, sqlb.T("SELECT # FROM person", sqlb.Columns((*person)(nil))))
+	sqlb.Scan(rows, &p)
+
+	func() {
+		var a account
+		row := sqlb.QueryRow(conn, ctx, sqlb.T("SELECT # FROM person", sqlb.Columns((*person)(nil))))
+		sqlb.Scan(row, &a) // want `sqlb.Scan into tmplcheck.account, but the query selects sqlb.Columns of tmplcheck.person`
+	}()
+
+	func() {
+		var p person
+		q := sqlb.T("SELECT # FROM person", sqlb.Columns((*person)(nil), "name"))
+		sqlb.Scan(sqlb.QueryRow(conn, ctx, q), &p) // want `sqlb.Scan into tmplcheck.person reads all columns, but sqlb.Columns excludes some of them`
+	}()
+
+	func() {
+		var a account
+		row := sqlb.QueryRow(conn, ctx, sqlb.T("INSERT INTO person (#) VALUES (@) RETURNING id", sqlb.Columns((*person)(nil), "id"), "foo"))
+		sqlb.Scan(row, &a)
+	}()
+
+	func(rows *sql.Rows) {
+		var a account
+		sqlb.T("SELECT # FROM person", sqlb.Columns((*person)(nil)))
+		sqlb.Scan(rows, &a)
+	}(nil)
+}
+
+func twoQueries(conn any, ctx context.Context) {
+	var (
+		p person
+		a account
+	)
+	prows, _ := sqlb.Query(conn, ctx, sqlb.T("SELECT # FROM person", sqlb.Columns((*person)(nil))))
+	q := sqlb.T("SELECT # FROM account", sqlb.Columns((*account)(nil)))
+	arows, _ := sqlb.Query(conn, ctx, q)
+	sqlb.Scan(prows, &p)
+	sqlb.Scan(arows, &a)
+	sqlb.Scan(arows, &p) // want `sqlb.Scan into tmplcheck.person, but the query selects sqlb.Columns of tmplcheck.account`
+	sqlb.Scan(prows, &a) // want `sqlb.Scan into tmplcheck.account, but the query selects sqlb.Columns of tmplcheck.person`
+}
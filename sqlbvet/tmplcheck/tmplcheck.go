@@ -0,0 +1,493 @@
+// tmplcheck パッケージは sqlb.T, sqlb.M の誤った使い方を検査するアナライザです.
+//
+// 定数のテンプレートを解析し, 実行時に T, M がエラーにする次のような誤りを報告します.
+//
+//   - T のプレースホルダと引数の数の不一致, 範囲外の @N
+//   - M のテンプレートの名前とマップリテラルのキーの不一致
+//   - # に string, []string 以外, $ に Sqler 以外, @ に展開できない型を渡している
+//   - SELECT に使った Columns と異なる構造体を Scan している
+package tmplcheck
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+const sqlbPath = "github.com/17e10/go-sqlb"
+
+// Analyzer は定数のテンプレートを持つ sqlb.T, sqlb.M の呼び出しを検査します.
+var Analyzer = &analysis.Analyzer{
+	Name:     "tmplcheck",
+	Doc:      "check placeholders, arguments and Columns/Scan pairs of sqlb.T and sqlb.M calls",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+// tpat, mpat は sqlb の T, M のプレースホルダと同じ構文です.
+var (
+	tpat = regexp.MustCompile(`!?==[ \t\n\r\f]{1,16}@[0-9]{0,2}|[@#$][0-9]{0,2}`)
+	mpat = regexp.MustCompile(`!?==[ \t\n\r\f]{1,16}@\w{1,64}|[@#$]\w{1,64}`)
+)
+
+// placeholder はテンプレート中のプレースホルダです.
+type placeholder struct {
+	text  string // テンプレート中の文字列
+	sigil byte   // @, #, $
+	eq    bool   // 擬似イコール構文
+	name  string // @ などの後ろの添字または名前
+}
+
+func parse(pat *regexp.Regexp, tmpl string) []placeholder {
+	var r []placeholder
+	for _, m := range pat.FindAllString(tmpl, -1) {
+		p := placeholder{text: m}
+		if m[0] == '!' || m[0] == '=' {
+			p.eq = true
+			m = m[strings.IndexByte(m, '@'):]
+		}
+		p.sigil, p.name = m[0], m[1:]
+		r = append(r, p)
+	}
+	return r
+}
+
+// checker は 1 つのパッケージの検査です.
+type checker struct {
+	pass  *analysis.Pass
+	sqlb  *types.Package
+	sqler *types.Interface
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	// テストで誤った使い方を確かめている sqlb 自身は検査しない
+	if pass.Pkg.Path() == sqlbPath {
+		return nil, nil
+	}
+
+	c := &checker{pass: pass}
+	ins := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	ins.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		call := n.(*ast.CallExpr)
+		switch c.sqlbFunc(call) {
+		case "T":
+			c.checkT(call)
+		case "M":
+			c.checkM(call)
+		}
+	})
+
+	ins.Preorder([]ast.Node{(*ast.FuncDecl)(nil), (*ast.FuncLit)(nil)}, func(n ast.Node) {
+		var body *ast.BlockStmt
+		switch f := n.(type) {
+		case *ast.FuncDecl:
+			body = f.Body
+		case *ast.FuncLit:
+			body = f.Body
+		}
+		if body != nil {
+			c.checkScan(body)
+		}
+	})
+	return nil, nil
+}
+
+// sqlbFunc は call が呼び出す sqlb の関数名を返します.
+func (c *checker) sqlbFunc(call *ast.CallExpr) string {
+	fn, ok := typeutil.Callee(c.pass.TypesInfo, call).(*types.Func)
+	if !ok {
+		return ""
+	}
+	if pkg := fn.Pkg(); pkg == nil || pkg.Path() != sqlbPath {
+		return ""
+	}
+	if c.sqlb == nil {
+		c.sqlb = fn.Pkg()
+		if obj := c.sqlb.Scope().Lookup("Sqler"); obj != nil {
+			c.sqler, _ = obj.Type().Underlying().(*types.Interface)
+		}
+	}
+	return fn.Name()
+}
+
+// template は定数のテンプレートを返します.
+func (c *checker) template(e ast.Expr) (string, bool) {
+	tv, ok := c.pass.TypesInfo.Types[e]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(tv.Value), true
+}
+
+func (c *checker) checkT(call *ast.CallExpr) {
+	if len(call.Args) == 0 {
+		return
+	}
+	tmpl, ok := c.template(call.Args[0])
+	if !ok || call.Ellipsis.IsValid() {
+		return
+	}
+	args := call.Args[1:]
+	used := make([]bool, len(args))
+
+	i := 0
+	for _, p := range parse(tpat, tmpl) {
+		if p.name != "" {
+			i, _ = strconv.Atoi(p.name)
+		}
+		if i >= len(args) {
+			c.pass.Reportf(call.Args[0].Pos(), "sqlb.T placeholder %q refers to argument %d, but %d arguments given", p.text, i, len(args))
+			i++
+			continue
+		}
+		used[i] = true
+		c.checkArg(p, args[i])
+		i++
+	}
+	for i, u := range used {
+		if !u {
+			c.pass.Reportf(args[i].Pos(), "sqlb.T argument %d is not used by the template", i)
+		}
+	}
+}
+
+func (c *checker) checkM(call *ast.CallExpr) {
+	if len(call.Args) != 2 {
+		return
+	}
+	tmpl, ok := c.template(call.Args[0])
+	if !ok {
+		return
+	}
+	lit, ok := ast.Unparen(call.Args[1]).(*ast.CompositeLit)
+	if !ok {
+		return
+	}
+
+	// キーがすべて定数のマップリテラルだけを検査する
+	params := make(map[string]ast.Expr)
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			return
+		}
+		k, ok := c.template(kv.Key)
+		if !ok {
+			return
+		}
+		params[k] = kv.Value
+	}
+
+	used := make(map[string]bool)
+	for _, p := range parse(mpat, tmpl) {
+		k := string(p.sigil) + p.name
+		v, ok := params[k]
+		if !ok {
+			c.pass.Reportf(call.Args[0].Pos(), "sqlb.M placeholder %q has no key %q in the map", p.text, k)
+			continue
+		}
+		used[k] = true
+		c.checkArg(p, v)
+	}
+	for _, elt := range lit.Elts {
+		kv := elt.(*ast.KeyValueExpr)
+		k, _ := c.template(kv.Key)
+		if !used[k] {
+			c.pass.Reportf(kv.Key.Pos(), "sqlb.M key %q is not used by the template", k)
+		}
+	}
+}
+
+// checkArg はプレースホルダ p に展開する引数 e の型を検査します.
+func (c *checker) checkArg(p placeholder, e ast.Expr) {
+	tv, ok := c.pass.TypesInfo.Types[e]
+	if !ok || tv.Type == nil || tv.IsNil() {
+		return
+	}
+	t := types.Unalias(tv.Type)
+	if types.IsInterface(t) && !(p.sigil == '@' && c.sqler != nil && types.Implements(t, c.sqler)) {
+		// 実行時まで型が分からない
+		return
+	}
+
+	switch p.sigil {
+	case '#':
+		if !isIdent(t) {
+			c.pass.Reportf(e.Pos(), "sqlb %q expects string or []string, got %s", p.text, typeString(t))
+		}
+	case '$':
+		if c.sqler != nil && !types.Implements(t, c.sqler) {
+			c.pass.Reportf(e.Pos(), "sqlb %q expects sqlb.Sqler, got %s", p.text, typeString(t))
+		}
+	case '@':
+		if !c.isValue(t, p.eq) {
+			c.pass.Reportf(e.Pos(), "sqlb %q cannot expand a value of type %s", p.text, typeString(t))
+		}
+	}
+}
+
+// isIdent は t が識別子として展開できるかを返します.
+func isIdent(t types.Type) bool {
+	if isBasic(t, types.String, types.UntypedString) {
+		return true
+	}
+	s, ok := t.(*types.Slice)
+	return ok && isBasic(types.Unalias(s.Elem()), types.String)
+}
+
+// isValue は t が値として展開できるかを返します.
+//
+// sqlb は型スイッチで値を展開するため, string などを基底型とする独自の型は
+// driver.Valuer を実装していなければ展開できません.
+func (c *checker) isValue(t types.Type, eq bool) bool {
+	if b, ok := t.(*types.Basic); ok {
+		return b.Info()&(types.IsBoolean|types.IsInteger|types.IsFloat|types.IsString) != 0 &&
+			b.Kind() != types.Uintptr
+	}
+	if hasValueMethod(t) {
+		return true
+	}
+	if isNamed(t, "time", "Time") ||
+		isNamed(t, sqlbPath, "LikePattern") ||
+		isNamed(t, sqlbPath, "SensitiveValue") {
+		return true
+	}
+	if _, ok := t.(*types.Named); ok {
+		return false
+	}
+
+	s, ok := t.(*types.Slice)
+	if !ok {
+		return false
+	}
+	elem := types.Unalias(s.Elem())
+	switch {
+	case isBasic(elem, types.Byte):
+		return true
+	case isEmptyInterface(elem):
+		return true
+	case isNamed(elem, sqlbPath, "Kv"):
+		return !eq
+	}
+	if s, ok := elem.Underlying().(*types.Slice); ok && isEmptyInterface(s.Elem()) {
+		return !eq
+	}
+	return false
+}
+
+// hasValueMethod は t が driver.Valuer を実装しているかを返します.
+func hasValueMethod(t types.Type) bool {
+	obj, _, _ := types.LookupFieldOrMethod(t, false, nil, "Value")
+	fn, ok := obj.(*types.Func)
+	if !ok {
+		return false
+	}
+	sig := fn.Type().(*types.Signature)
+	return sig.Params().Len() == 0 && sig.Results().Len() == 2
+}
+
+// typeString は t をパッケージ名で修飾した文字列にします.
+func typeString(t types.Type) string {
+	return types.TypeString(t, func(p *types.Package) string { return p.Name() })
+}
+
+func isBasic(t types.Type, kinds ...types.BasicKind) bool {
+	b, ok := t.(*types.Basic)
+	if !ok {
+		return false
+	}
+	for _, k := range kinds {
+		if b.Kind() == k {
+			return true
+		}
+	}
+	return false
+}
+
+func isNamed(t types.Type, path, name string) bool {
+	n, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := n.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == path && obj.Name() == name
+}
+
+func isEmptyInterface(t types.Type) bool {
+	i, ok := t.Underlying().(*types.Interface)
+	return ok && i.Empty()
+}
+
+// checkScan は関数 body の sqlb.Scan が, 行を返したクエリの sqlb.Columns と同じ構造体かを検査します.
+//
+// Scan は構造体のすべてのカラムを Columns と同じ順序で受け取るため,
+// 異なる構造体や excludes を指定した Columns とは組み合わせられません.
+//
+// Scan の行は sqlb.Query, sqlb.QueryRow の呼び出しまで body の中の代入をたどって求めます.
+// 定数のテンプレートを持つ SELECT の sqlb.T, sqlb.M までたどれないときは検査しません.
+func (c *checker) checkScan(body *ast.BlockStmt) {
+	assigns := c.assigns(body)
+	ast.Inspect(body, func(n ast.Node) bool {
+		if _, ok := n.(*ast.FuncLit); ok {
+			return false
+		}
+		call, ok := n.(*ast.CallExpr)
+		if !ok || c.sqlbFunc(call) != "Scan" || len(call.Args) != 2 {
+			return true
+		}
+		query := c.resolveCall(call.Args[0], assigns)
+		if query == nil || len(query.Args) != 3 {
+			return true
+		}
+		if f := c.sqlbFunc(query); f != "Query" && f != "QueryRow" {
+			return true
+		}
+		if tmpl := c.resolveCall(query.Args[2], assigns); tmpl != nil {
+			c.checkScanColumns(call, tmpl)
+		}
+		return true
+	})
+}
+
+// checkScanColumns は scan が SELECT のテンプレート tmpl に使った sqlb.Columns と同じ構造体かを検査します.
+func (c *checker) checkScanColumns(scan, tmpl *ast.CallExpr) {
+	if f := c.sqlbFunc(tmpl); (f != "T" && f != "M") || len(tmpl.Args) == 0 {
+		return
+	}
+	if s, ok := c.template(tmpl.Args[0]); !ok || !strings.Contains(strings.ToUpper(s), "SELECT") {
+		return
+	}
+	type columns struct {
+		typ      types.Type
+		excludes bool
+	}
+	var cols []columns
+	ast.Inspect(tmpl, func(n ast.Node) bool {
+		if col, ok := n.(*ast.CallExpr); ok && c.sqlbFunc(col) == "Columns" {
+			if t := c.typeArg(col); t != nil {
+				cols = append(cols, columns{t, len(col.Args) > 1})
+			}
+		}
+		return true
+	})
+	if len(cols) == 0 {
+		return
+	}
+
+	t := c.typeArg(scan)
+	if t == nil {
+		return
+	}
+	var found, excluded bool
+	for _, col := range cols {
+		if types.Identical(col.typ, t) {
+			if col.excludes {
+				excluded = true
+			} else {
+				found = true
+			}
+		}
+	}
+	switch {
+	case found:
+	case excluded:
+		c.pass.Reportf(scan.Pos(), "sqlb.Scan into %s reads all columns, but sqlb.Columns excludes some of them", typeString(t))
+	default:
+		c.pass.Reportf(scan.Pos(), "sqlb.Scan into %s, but the query selects sqlb.Columns of %s", typeString(t), typeString(cols[0].typ))
+	}
+}
+
+// assigns は body の中で変数に代入した式を返します.
+//
+// 2 回以上代入した変数は式を特定できないので nil にします.
+func (c *checker) assigns(body *ast.BlockStmt) map[types.Object]ast.Expr {
+	r := make(map[types.Object]ast.Expr)
+	add := func(lhs []ast.Expr, rhs []ast.Expr) {
+		for i, l := range lhs {
+			id, ok := l.(*ast.Ident)
+			if !ok {
+				continue
+			}
+			obj := c.pass.TypesInfo.ObjectOf(id)
+			if obj == nil {
+				continue
+			}
+			var e ast.Expr
+			switch {
+			case len(lhs) == len(rhs):
+				e = rhs[i]
+			case len(rhs) == 1 && i == 0:
+				e = rhs[0]
+			}
+			if _, dup := r[obj]; dup {
+				e = nil
+			}
+			r[obj] = e
+		}
+	}
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.AssignStmt:
+			add(n.Lhs, n.Rhs)
+		case *ast.ValueSpec:
+			lhs := make([]ast.Expr, len(n.Names))
+			for i, id := range n.Names {
+				lhs[i] = id
+			}
+			add(lhs, n.Values)
+		}
+		return true
+	})
+	return r
+}
+
+// resolveCall は e が表す関数呼び出しを, 変数に代入した式をたどって返します.
+func (c *checker) resolveCall(e ast.Expr, assigns map[types.Object]ast.Expr) *ast.CallExpr {
+	// 循環する代入で止まらないように深さを制限する
+	for depth := 0; depth < 8; depth++ {
+		switch x := ast.Unparen(e).(type) {
+		case *ast.CallExpr:
+			return x
+		case *ast.Ident:
+			next, ok := assigns[c.pass.TypesInfo.Uses[x]]
+			if !ok || next == nil {
+				return nil
+			}
+			e = next
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// typeArg は総称関数の呼び出し call の型引数を返します.
+func (c *checker) typeArg(call *ast.CallExpr) types.Type {
+	var id *ast.Ident
+	switch fun := ast.Unparen(call.Fun).(type) {
+	case *ast.Ident:
+		id = fun
+	case *ast.SelectorExpr:
+		id = fun.Sel
+	case *ast.IndexExpr:
+		return c.pass.TypesInfo.TypeOf(fun.Index)
+	default:
+		return nil
+	}
+	inst, ok := c.pass.TypesInfo.Instances[id]
+	if !ok || inst.TypeArgs.Len() == 0 {
+		return nil
+	}
+	return inst.TypeArgs.At(0)
+}
@@ -0,0 +1,17 @@
+package tmplcheck_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/17e10/go-sqlb/sqlbvet/tmplcheck"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	testdata, err := filepath.Abs(filepath.Join("..", "testdata"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	analysistest.Run(t, testdata, tmplcheck.Analyzer, "tmplcheck")
+}
@@ -0,0 +1,170 @@
+// sqlstat パッケージは SQL の実行時間やエラー数を集計します.
+//
+// sqlt のインターフェイスを受け取るコードは Wrap した Conn を渡すだけで計測できます.
+//
+//	stats := &sqlstat.Stats{SlowThreshold: time.Second, OnSlow: logSlow}
+//	stats.Publish("sql")
+//	conn := sqlstat.Wrap(db, stats)
+package sqlstat
+
+import (
+	"context"
+	"expvar"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/17e10/go-sqlb"
+	"github.com/17e10/go-sqlb/sqlt"
+)
+
+// Bounds は実行時間のヒストグラムのバケットの上限です.
+//
+// QueryStats.Histogram の i 番目の要素は Bounds[i] 以下の実行回数で,
+// 最後の要素はどの上限も超えた実行回数です.
+// Stats は集計を始めるときに Bounds をコピーするので,
+// 変更は次の集計または Reset の後から反映されます.
+var Bounds = []time.Duration{
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	5 * time.Second,
+}
+
+// QueryStats は 1 つのフィンガープリントの集計結果です.
+type QueryStats struct {
+	Fingerprint string        `json:"fingerprint"`
+	Count       int64         `json:"count"`
+	Errors      int64         `json:"errors"`
+	Total       time.Duration `json:"total_ns"`
+	Max         time.Duration `json:"max_ns"`
+	Histogram   []int64       `json:"histogram"`
+}
+
+// SlowQuery は SlowThreshold を超えた SQL の情報です.
+type SlowQuery struct {
+	Fingerprint string
+	Query       string
+	Args        []any
+	Duration    time.Duration
+	Err         error
+}
+
+// Stats は SQL の実行時間の分布やエラー数をクエリのフィンガープリントごとに集計します.
+//
+// Stats は sqlb.Hook を実装するので sqlb.AddHook や sqlb.WithHooks にも使えます.
+// ゼロ値の Stats はそのまま使えます.
+type Stats struct {
+	// SlowThreshold 以上かかった SQL を OnSlow に通知します. 0 のときは通知しません.
+	SlowThreshold time.Duration
+	OnSlow        func(q SlowQuery)
+
+	// Fingerprint は SQL から集計のキーを作成します. nil のときは sqlb.Fingerprint を使います.
+	Fingerprint func(query string) string
+
+	mu      sync.Mutex
+	queries map[string]*QueryStats
+	bounds  []time.Duration
+}
+
+// Before は何もしません.
+func (s *Stats) Before(ctx context.Context, _ *sqlb.QueryEvent) context.Context {
+	return ctx
+}
+
+// After は e の実行時間とエラーを集計します.
+func (s *Stats) After(_ context.Context, e *sqlb.QueryEvent) {
+	fp := s.fingerprint(e.Query)
+
+	s.mu.Lock()
+	if s.queries == nil {
+		s.queries = make(map[string]*QueryStats)
+		s.bounds = append([]time.Duration{}, Bounds...)
+	}
+	q := s.queries[fp]
+	if q == nil {
+		q = &QueryStats{Fingerprint: fp, Histogram: make([]int64, len(s.bounds)+1)}
+		s.queries[fp] = q
+	}
+	q.Count++
+	if e.Err != nil {
+		q.Errors++
+	}
+	q.Total += e.Duration
+	if e.Duration > q.Max {
+		q.Max = e.Duration
+	}
+	q.Histogram[bucket(s.bounds, e.Duration)]++
+	s.mu.Unlock()
+
+	if s.SlowThreshold > 0 && e.Duration >= s.SlowThreshold && s.OnSlow != nil {
+		s.OnSlow(SlowQuery{fp, e.Query, e.Args, e.Duration, e.Err})
+	}
+}
+
+// fingerprint は query の集計のキーを返します.
+func (s *Stats) fingerprint(query string) string {
+	if s.Fingerprint != nil {
+		return s.Fingerprint(query)
+	}
+	fp, _ := sqlb.Fingerprint(query)
+	return fp
+}
+
+// bucket は実行時間 d が入るヒストグラムのバケットの位置を返します.
+func bucket(bounds []time.Duration, d time.Duration) int {
+	return sort.Search(len(bounds), func(i int) bool { return d <= bounds[i] })
+}
+
+// Snapshot は集計結果を実行回数の多い順に返します.
+func (s *Stats) Snapshot() []QueryStats {
+	s.mu.Lock()
+	r := make([]QueryStats, 0, len(s.queries))
+	for _, q := range s.queries {
+		c := *q
+		c.Histogram = append([]int64{}, q.Histogram...)
+		r = append(r, c)
+	}
+	s.mu.Unlock()
+
+	sort.Slice(r, func(i, j int) bool {
+		if r[i].Count != r[j].Count {
+			return r[i].Count > r[j].Count
+		}
+		return r[i].Fingerprint < r[j].Fingerprint
+	})
+	return r
+}
+
+// Reset は集計結果を破棄します.
+func (s *Stats) Reset() {
+	s.mu.Lock()
+	s.queries = nil
+	s.mu.Unlock()
+}
+
+// Publish は集計結果を name という名前で expvar に公開します.
+//
+// expvar.Publish と同様に同じ name で 2 回呼び出すとパニックします.
+func (s *Stats) Publish(name string) {
+	expvar.Publish(name, expvar.Func(func() any {
+		return s.Snapshot()
+	}))
+}
+
+// Conn は SQL の実行を Stats に集計する sqlt.Conn です.
+//
+// BeginTx で開始したトランザクションの SQL も Stats に集計します.
+type Conn struct {
+	*sqlb.HookedConn
+	Stats *Stats
+}
+
+// Wrap は *sql.DB, *sql.Tx, *sql.Conn などの conn を stats に集計する Conn でラップします.
+func Wrap(conn sqlt.Conn, stats *Stats) *Conn {
+	return &Conn{sqlb.WithHooks(conn, stats), stats}
+}
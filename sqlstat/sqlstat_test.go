@@ -0,0 +1,153 @@
+package sqlstat
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/17e10/go-sqlb"
+	"github.com/17e10/go-sqlb/sqlt"
+)
+
+var (
+	_ sqlt.Conn       = (*Conn)(nil)
+	_ sqlt.TxBeginner = (*Conn)(nil)
+)
+
+type testConn struct {
+	sqlt.TestExecer
+}
+
+func (c *testConn) PrepareContext(context.Context, string) (*sql.Stmt, error) {
+	return nil, errors.New("not supported")
+}
+
+func (c *testConn) QueryContext(context.Context, string, ...any) (*sql.Rows, error) {
+	return nil, errors.New("not supported")
+}
+
+func (c *testConn) QueryRowContext(context.Context, string, ...any) *sql.Row {
+	return nil
+}
+
+func TestBucket(t *testing.T) {
+	tests := []struct {
+		src  time.Duration
+		want int
+	}{
+		{0, 0},
+		{time.Millisecond, 0},
+		{2 * time.Millisecond, 1},
+		{time.Second, 6},
+		{time.Minute, len(Bounds)},
+	}
+
+	for _, te := range tests {
+		got := bucket(Bounds, te.src)
+		if got != te.want {
+			t.Errorf("bucket(%v) = %d, want %d", te.src, got, te.want)
+		}
+	}
+}
+
+func TestStats(t *testing.T) {
+	var slow []SlowQuery
+	s := &Stats{
+		SlowThreshold: time.Second,
+		OnSlow:        func(q SlowQuery) { slow = append(slow, q) },
+	}
+	ctx := context.TODO()
+	errFailed := errors.New("failed")
+
+	s.After(ctx, &sqlb.QueryEvent{Query: "SELECT  1", Duration: 2 * time.Millisecond})
+	s.After(ctx, &sqlb.QueryEvent{Query: "SELECT 1\n", Duration: 2 * time.Second, Err: errFailed})
+	s.After(ctx, &sqlb.QueryEvent{Query: "SELECT count(*) FROM t", Duration: time.Millisecond})
+
+	want := []QueryStats{
+		{"SELECT ?", 2, 1, 2002 * time.Millisecond, 2 * time.Second, []int64{0, 1, 0, 0, 0, 0, 0, 1, 0}},
+		{"SELECT count(*) FROM t", 1, 0, time.Millisecond, time.Millisecond, []int64{1, 0, 0, 0, 0, 0, 0, 0, 0}},
+	}
+	got := s.Snapshot()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("%s = %+v, want %+v", "test Stats", got, want)
+	}
+	if len(slow) != 1 || slow[0].Fingerprint != "SELECT ?" || slow[0].Err != errFailed {
+		t.Errorf("%s slow %+v", "test Stats", slow)
+	}
+
+	s.Reset()
+	if got := s.Snapshot(); len(got) != 0 {
+		t.Errorf("%s after Reset = %+v", "test Stats", got)
+	}
+}
+
+func TestStatsBounds(t *testing.T) {
+	saved := Bounds
+	t.Cleanup(func() { Bounds = saved })
+
+	s := &Stats{}
+	ctx := context.TODO()
+	s.After(ctx, &sqlb.QueryEvent{Query: "SELECT 1", Duration: time.Minute})
+	Bounds = append(append([]time.Duration{}, saved...), 10*time.Second, time.Hour)
+	s.After(ctx, &sqlb.QueryEvent{Query: "SELECT 1", Duration: time.Minute})
+
+	want := []int64{0, 0, 0, 0, 0, 0, 0, 0, 2}
+	if got := s.Snapshot(); len(got) != 1 || !reflect.DeepEqual(got[0].Histogram, want) {
+		t.Errorf("%s = %+v, want histogram %v", "test Stats Bounds", got, want)
+	}
+
+	s.Reset()
+	s.After(ctx, &sqlb.QueryEvent{Query: "SELECT 1", Duration: time.Minute})
+	want = []int64{0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 0}
+	if got := s.Snapshot(); len(got) != 1 || !reflect.DeepEqual(got[0].Histogram, want) {
+		t.Errorf("%s after Reset = %+v, want histogram %v", "test Stats Bounds", got, want)
+	}
+}
+
+func TestWrap(t *testing.T) {
+	s := &Stats{}
+	s.Publish("sqlstat_test")
+
+	conn := Wrap(&testConn{}, s)
+	ctx := context.TODO()
+	sqlb.Exec(conn, ctx, sqlb.T("DELETE FROM t"))
+	conn.QueryContext(ctx, "SELECT 1")
+
+	got := s.Snapshot()
+	if len(got) != 2 || got[0].Count != 1 || got[1].Count != 1 || got[1].Errors != 1 {
+		t.Errorf("%s = %+v", "test Wrap", got)
+	}
+
+	var published []QueryStats
+	if err := json.Unmarshal([]byte(expvar.Get("sqlstat_test").String()), &published); err != nil {
+		t.Fatalf("%s errored %v", "test Publish", err)
+	}
+	if !reflect.DeepEqual(published, got) {
+		t.Errorf("%s = %+v, want %+v", "test Publish", published, got)
+	}
+}
+
+func TestWrapBeginTx(t *testing.T) {
+	s := &Stats{}
+	tc := &sqlt.TestConn{RowsAffected: 1}
+	conn := Wrap(tc, s)
+
+	ctx := context.TODO()
+	err := sqlb.InTx(ctx, conn, nil, func(tx sqlt.Conn) error {
+		_, err := sqlb.Exec(tx, ctx, sqlb.T("DELETE FROM t"))
+		return err
+	})
+	if err != nil {
+		t.Fatalf("%s errored %v", "test Wrap BeginTx", err)
+	}
+
+	got := s.Snapshot()
+	if len(got) != 1 || got[0].Count != 1 || tc.Committed != 1 {
+		t.Errorf("%s = %+v, committed %d", "test Wrap BeginTx", got, tc.Committed)
+	}
+}
@@ -25,7 +25,48 @@ type RowsScanner interface {
 	Scan(dest ...any) error
 }
 
+// ColumnsScanner は database/sql の Rows の ColumnTypes, Scan メソッドをラップするインターフェイスです.
+type ColumnsScanner interface {
+	ColumnTypes() ([]*sql.ColumnType, error)
+	Scan(dest ...any) error
+}
+
 // Execer は database/sql の ExecContext メソッドをラップするインターフェイスです.
 type Execer interface {
 	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
 }
+
+// QueryExecer は Queryer と Execer を合わせたインターフェイスです.
+type QueryExecer interface {
+	Queryer
+	Execer
+}
+
+// Beginner は database/sql の BeginTx メソッドをラップするインターフェイスです.
+type Beginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// TxBeginner は Tx を返す BeginTx メソッドを持つインターフェイスです.
+//
+// sqlb.HookedConn のように *sql.Tx 以外のトランザクションを開始する conn が実装します.
+type TxBeginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (Tx, error)
+}
+
+// Conn は *sql.DB, *sql.Tx, *sql.Conn に共通するメソッドのインターフェイスです.
+//
+// Conn を受け取るようにすればトランザクションの内外どちらからでも呼び出せます.
+type Conn interface {
+	Preparer
+	Queryer
+	QueryRower
+	Execer
+}
+
+// Tx は *sql.Tx のメソッドをラップするインターフェイスです.
+type Tx interface {
+	Conn
+	Commit() error
+	Rollback() error
+}
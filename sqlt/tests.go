@@ -3,16 +3,22 @@ package sqlt
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
+
+	"github.com/17e10/go-sqlb/internal/testdriver"
 )
 
-type testResult struct{}
+type testResult struct {
+	lastInsertId int64
+	rowsAffected int64
+}
 
-func (testResult) LastInsertId() (int64, error) {
-	return 0, nil
+func (r testResult) LastInsertId() (int64, error) {
+	return r.lastInsertId, nil
 }
 
-func (testResult) RowsAffected() (int64, error) {
-	return 0, nil
+func (r testResult) RowsAffected() (int64, error) {
+	return r.rowsAffected, nil
 }
 
 type TestExeced struct {
@@ -22,11 +28,15 @@ type TestExeced struct {
 
 type TestExecer struct {
 	Execed []TestExeced
+
+	// LastInsertId, RowsAffected は ExecContext が返す sql.Result の値です.
+	LastInsertId int64
+	RowsAffected int64
 }
 
 func (e *TestExecer) ExecContext(_ context.Context, query string, args ...any) (sql.Result, error) {
 	e.Execed = append(e.Execed, TestExeced{query, args})
-	return testResult{}, nil
+	return testResult{e.LastInsertId, e.RowsAffected}, nil
 }
 
 type NullExecer struct{}
@@ -34,3 +44,151 @@ type NullExecer struct{}
 func (e NullExecer) ExecContext(_ context.Context, query string, args ...any) (sql.Result, error) {
 	return testResult{}, nil
 }
+
+// TestRows はテストダブルが返す結果の行です.
+//
+// Types はカラムのデータベースの型名で, sql.ColumnType の DatabaseTypeName が返します.
+type TestRows struct {
+	Columns []string
+	Types   []string
+	Values  [][]any
+}
+
+type TestQueried struct {
+	Query string
+	Args  []any
+}
+
+// TestQueryer は QueryContext の呼び出しを記録する Queryer です.
+type TestQueryer struct {
+	Queried []TestQueried
+
+	// Rows, Err は QueryContext が返す結果です.
+	Rows TestRows
+	Err  error
+}
+
+func (q *TestQueryer) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	h := &testdriver.Handler{Query: q.query}
+	return testdriver.DB().QueryContext(testdriver.WithHandler(ctx, h), query, args...)
+}
+
+func (q *TestQueryer) query(query string, args []any) (testdriver.Rows, error) {
+	q.Queried = append(q.Queried, TestQueried{query, args})
+	return testdriver.Rows(q.Rows), q.Err
+}
+
+// TestQueryRower は QueryRowContext の呼び出しを記録する QueryRower です.
+type TestQueryRower struct {
+	Queried []TestQueried
+
+	// Rows, Err は QueryRowContext が返す結果です.
+	Rows TestRows
+	Err  error
+}
+
+func (q *TestQueryRower) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	h := &testdriver.Handler{Query: q.query}
+	return testdriver.DB().QueryRowContext(testdriver.WithHandler(ctx, h), query, args...)
+}
+
+func (q *TestQueryRower) query(query string, args []any) (testdriver.Rows, error) {
+	q.Queried = append(q.Queried, TestQueried{query, args})
+	return testdriver.Rows(q.Rows), q.Err
+}
+
+// TestPreparer は PrepareContext の呼び出しを記録する Preparer です.
+//
+// Err が nil でないとき PrepareContext は Err を返します.
+// そうでないとき返した *sql.Stmt の Query, Exec は Rows, LastInsertId, RowsAffected を返します.
+type TestPreparer struct {
+	Prepared []string
+
+	Rows         TestRows
+	LastInsertId int64
+	RowsAffected int64
+	Err          error
+}
+
+func (p *TestPreparer) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	h := &testdriver.Handler{
+		Prepare: func(query string) error {
+			p.Prepared = append(p.Prepared, query)
+			return p.Err
+		},
+		Query: func(string, []any) (testdriver.Rows, error) {
+			return testdriver.Rows(p.Rows), nil
+		},
+		Exec: func(string, []any) (driver.Result, error) {
+			return testResult{p.LastInsertId, p.RowsAffected}, nil
+		},
+	}
+	return testdriver.DB().PrepareContext(testdriver.WithHandler(ctx, h), query)
+}
+
+// TestConn はすべての操作を記録する Conn です.
+//
+// BeginTx が返す *sql.Tx の操作も TestConn に記録します.
+// Rows, LastInsertId, RowsAffected, Err は Query, Exec が返す結果です.
+type TestConn struct {
+	Prepared   []string
+	Queried    []TestQueried
+	Execed     []TestExeced
+	Begun      int
+	Committed  int
+	RolledBack int
+
+	Rows         TestRows
+	LastInsertId int64
+	RowsAffected int64
+	Err          error
+}
+
+func (c *TestConn) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return testdriver.DB().PrepareContext(testdriver.WithHandler(ctx, c.handler()), query)
+}
+
+func (c *TestConn) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return testdriver.DB().QueryContext(testdriver.WithHandler(ctx, c.handler()), query, args...)
+}
+
+func (c *TestConn) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return testdriver.DB().QueryRowContext(testdriver.WithHandler(ctx, c.handler()), query, args...)
+}
+
+func (c *TestConn) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return testdriver.DB().ExecContext(testdriver.WithHandler(ctx, c.handler()), query, args...)
+}
+
+func (c *TestConn) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	return testdriver.DB().BeginTx(testdriver.WithHandler(ctx, c.handler()), opts)
+}
+
+func (c *TestConn) handler() *testdriver.Handler {
+	return &testdriver.Handler{
+		Prepare: func(query string) error {
+			c.Prepared = append(c.Prepared, query)
+			return nil
+		},
+		Query: func(query string, args []any) (testdriver.Rows, error) {
+			c.Queried = append(c.Queried, TestQueried{query, args})
+			return testdriver.Rows(c.Rows), c.Err
+		},
+		Exec: func(query string, args []any) (driver.Result, error) {
+			c.Execed = append(c.Execed, TestExeced{query, args})
+			return testResult{c.LastInsertId, c.RowsAffected}, c.Err
+		},
+		Begin: func() error {
+			c.Begun++
+			return nil
+		},
+		Commit: func() error {
+			c.Committed++
+			return nil
+		},
+		Rollback: func() error {
+			c.RolledBack++
+			return nil
+		},
+	}
+}
@@ -0,0 +1,156 @@
+package sqlt_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/17e10/go-sqlb"
+	_ "github.com/17e10/go-sqlb/dialect/mysql"
+	"github.com/17e10/go-sqlb/sqlt"
+)
+
+var (
+	_ sqlt.Queryer     = (*sqlt.TestQueryer)(nil)
+	_ sqlt.QueryRower  = (*sqlt.TestQueryRower)(nil)
+	_ sqlt.Preparer    = (*sqlt.TestPreparer)(nil)
+	_ sqlt.Conn        = (*sqlt.TestConn)(nil)
+	_ sqlt.QueryExecer = (*sqlt.TestConn)(nil)
+	_ sqlt.Beginner    = (*sqlt.TestConn)(nil)
+)
+
+type person struct {
+	Id   int64
+	Name string
+}
+
+var persons = sqlt.TestRows{
+	Columns: []string{"id", "name"},
+	Values:  [][]any{{1, "Olivia"}, {2, "Kenny"}},
+}
+
+func TestQueryer(t *testing.T) {
+	q := &sqlt.TestQueryer{Rows: persons}
+	ctx := context.TODO()
+
+	rows, err := q.QueryContext(ctx, "SELECT id, name FROM person WHERE age > ?", 20)
+	if err != nil {
+		t.Fatalf("%s errored %v", "test Queryer", err)
+	}
+	var got []person
+	for rows.Next() {
+		var p person
+		if err := sqlb.Scan(rows, &p); err != nil {
+			t.Fatalf("%s Scan errored %v", "test Queryer", err)
+		}
+		got = append(got, p)
+	}
+	rows.Close()
+
+	want := []person{{1, "Olivia"}, {2, "Kenny"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("%s = %+v, want %+v", "test Queryer", got, want)
+	}
+	wantq := []sqlt.TestQueried{{"SELECT id, name FROM person WHERE age > ?", []any{20}}}
+	if !reflect.DeepEqual(q.Queried, wantq) {
+		t.Errorf("%s Queried = %+v, want %+v", "test Queryer", q.Queried, wantq)
+	}
+
+	q.Err = errors.New("failed")
+	if _, err := q.QueryContext(ctx, "SELECT 1"); err != q.Err {
+		t.Errorf("%s errored %v, want %v", "test Queryer", err, q.Err)
+	}
+}
+
+func TestQueryRower(t *testing.T) {
+	q := &sqlt.TestQueryRower{}
+	ctx := context.TODO()
+
+	var p person
+	if err := sqlb.Scan(q.QueryRowContext(ctx, "SELECT id, name FROM person"), &p); err != sql.ErrNoRows {
+		t.Errorf("%s errored %v, want %v", "test QueryRower", err, sql.ErrNoRows)
+	}
+
+	q.Rows = persons
+	if err := sqlb.Scan(q.QueryRowContext(ctx, "SELECT id, name FROM person"), &p); err != nil || p != (person{1, "Olivia"}) {
+		t.Errorf("%s = %+v, %v", "test QueryRower", p, err)
+	}
+	if len(q.Queried) != 2 {
+		t.Errorf("%s Queried = %+v", "test QueryRower", q.Queried)
+	}
+}
+
+func TestPreparer(t *testing.T) {
+	p := &sqlt.TestPreparer{Rows: persons, RowsAffected: 3}
+	ctx := context.TODO()
+
+	st, err := p.PrepareContext(ctx, "DELETE FROM person WHERE id = ?")
+	if err != nil {
+		t.Fatalf("%s errored %v", "test Preparer", err)
+	}
+	defer st.Close()
+	res, err := st.ExecContext(ctx, 1)
+	if err != nil {
+		t.Fatalf("%s Exec errored %v", "test Preparer", err)
+	}
+	if n, _ := res.RowsAffected(); n != 3 {
+		t.Errorf("%s RowsAffected = %d, want %d", "test Preparer", n, 3)
+	}
+	if !reflect.DeepEqual(p.Prepared, []string{"DELETE FROM person WHERE id = ?"}) {
+		t.Errorf("%s Prepared = %q", "test Preparer", p.Prepared)
+	}
+
+	p.Err = errors.New("failed")
+	if _, err := p.PrepareContext(ctx, "SELECT 1"); err != p.Err {
+		t.Errorf("%s errored %v, want %v", "test Preparer", err, p.Err)
+	}
+	if len(p.Prepared) != 2 {
+		t.Errorf("%s Prepared = %q", "test Preparer", p.Prepared)
+	}
+}
+
+func TestConn(t *testing.T) {
+	c := &sqlt.TestConn{Rows: persons, LastInsertId: 10, RowsAffected: 1}
+	ctx := context.TODO()
+
+	err := sqlb.InTx(ctx, c, nil, func(tx sqlt.Conn) error {
+		res, err := sqlb.Exec(tx, ctx, sqlb.Insert("person").Columns("name").Values("Olivia"))
+		if err != nil {
+			return err
+		}
+		if id, _ := res.LastInsertId(); id != 10 {
+			t.Errorf("%s LastInsertId = %d, want %d", "test Conn", id, 10)
+		}
+		var p person
+		err = sqlb.Scan(sqlb.QueryRow(tx, ctx, sqlb.T("SELECT id, name FROM person")), &p)
+		if err != nil || p != (person{1, "Olivia"}) {
+			t.Errorf("%s QueryRow = %+v, %v", "test Conn", p, err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("%s errored %v", "test Conn", err)
+	}
+
+	errFailed := errors.New("failed")
+	err = sqlb.InTx(ctx, c, nil, func(tx sqlt.Conn) error {
+		return errFailed
+	})
+	if err != errFailed {
+		t.Errorf("%s errored %v, want %v", "test Conn", err, errFailed)
+	}
+
+	wante := []sqlt.TestExeced{{"INSERT INTO `person` (`name`) VALUES ('Olivia')", nil}}
+	wantq := []sqlt.TestQueried{{"SELECT id, name FROM person", nil}}
+	if !reflect.DeepEqual(c.Execed, wante) {
+		t.Errorf("%s Execed = %+v, want %+v", "test Conn", c.Execed, wante)
+	}
+	if !reflect.DeepEqual(c.Queried, wantq) {
+		t.Errorf("%s Queried = %+v, want %+v", "test Conn", c.Queried, wantq)
+	}
+	if c.Begun != 2 || c.Committed != 1 || c.RolledBack != 1 {
+		t.Errorf("%s Begun, Committed, RolledBack = %d, %d, %d, want 2, 1, 1", "test Conn", c.Begun, c.Committed, c.RolledBack)
+	}
+}
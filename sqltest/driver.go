@@ -0,0 +1,86 @@
+package sqltest
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+
+	"github.com/17e10/go-sqlb/internal/testdriver"
+)
+
+var errNoMock = errors.New("sqltest: no such mock")
+
+// drv は Mock に接続する driver.Driver です.
+type drv struct{}
+
+func (drv) Open(dsn string) (driver.Conn, error) {
+	m, ok := mocks.Load(dsn)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", errNoMock, dsn)
+	}
+	return testdriver.NewConn(m.(*Mock).handler()), nil
+}
+
+// handler は操作を Mock と照合する testdriver.Handler を返します.
+//
+// プリペアドステートメントは実行時に照合します.
+func (m *Mock) handler() *testdriver.Handler {
+	return &testdriver.Handler{
+		Query: func(query string, _ []any) (testdriver.Rows, error) {
+			e, err := m.match(kindQuery, query)
+			if err != nil {
+				return testdriver.Rows{}, err
+			}
+			return testdriver.Rows{Columns: e.columns, Values: e.rows}, nil
+		},
+		Exec: func(query string, _ []any) (driver.Result, error) {
+			e, err := m.match(kindExec, query)
+			if err != nil {
+				return nil, err
+			}
+			if e.result == nil {
+				return result{}, nil
+			}
+			return e.result, nil
+		},
+		Begin: func() error {
+			_, err := m.match(kindBegin, "")
+			return err
+		},
+		Commit: func() error {
+			_, err := m.match(kindCommit, "")
+			return err
+		},
+		Rollback: func() error {
+			_, err := m.match(kindRollback, "")
+			return err
+		},
+	}
+}
+
+// match は操作 k, SQL query に一致する期待を返します.
+// 期待が WillReturnError で登録したエラーを持つときはそのエラーを返します.
+func (m *Mock) match(k kind, query string) (*Expectation, error) {
+	e, err := m.next(k, query)
+	if err != nil {
+		return nil, err
+	}
+	if e.err != nil {
+		return nil, e.err
+	}
+	return e, nil
+}
+
+// result は WillReturnResult で登録した driver.Result です.
+type result struct {
+	lastInsertId int64
+	rowsAffected int64
+}
+
+func (r result) LastInsertId() (int64, error) {
+	return r.lastInsertId, nil
+}
+
+func (r result) RowsAffected() (int64, error) {
+	return r.rowsAffected, nil
+}
@@ -0,0 +1,233 @@
+// sqltest パッケージはテスト用の database/sql ドライバを提供します.
+//
+// 実行される SQL とその結果をあらかじめ Mock に登録しておくと,
+// 実際のデータベースなしで Query, QueryRow, Scan などを使うコードをテストできます.
+//
+//	db, mock := sqltest.New()
+//	mock.ExpectQuery("SELECT `id`, `name` FROM `person`").
+//		WillReturnRows([]string{"id", "name"}, []any{1, "Olivia"})
+//
+//	// テスト対象のコードを db で実行する
+//
+//	if err := mock.ExpectationsWereMet(); err != nil {
+//		t.Error(err)
+//	}
+package sqltest
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/17e10/go-sqlb"
+)
+
+// DriverName は database/sql に登録したドライバの名前です.
+const DriverName = "sqltest"
+
+var (
+	errUnexpected = errors.New("unexpected call")
+	errNotMet     = errors.New("expectation was not met")
+)
+
+// kind は期待する操作の種類です.
+type kind string
+
+const (
+	kindQuery    kind = "query"
+	kindExec     kind = "exec"
+	kindBegin    kind = "begin"
+	kindCommit   kind = "commit"
+	kindRollback kind = "rollback"
+)
+
+// Expectation は 1 回の操作に対する期待とその結果です.
+type Expectation struct {
+	kind  kind
+	query string
+	re    *regexp.Regexp
+
+	columns []string
+	rows    [][]any
+	result  driver.Result
+	err     error
+
+	met bool
+}
+
+// WillReturnRows は Query の結果として columns と rows を返すようにします.
+func (e *Expectation) WillReturnRows(columns []string, rows ...[]any) *Expectation {
+	e.columns, e.rows = columns, rows
+	return e
+}
+
+// WillReturnResult は Exec の結果として lastInsertId と rowsAffected を返すようにします.
+func (e *Expectation) WillReturnResult(lastInsertId, rowsAffected int64) *Expectation {
+	e.result = result{lastInsertId, rowsAffected}
+	return e
+}
+
+// WillReturnError は操作が err を返すようにします.
+func (e *Expectation) WillReturnError(err error) *Expectation {
+	e.err = err
+	return e
+}
+
+// match は操作 k, SQL query が期待と一致するかを返します.
+func (e *Expectation) match(k kind, query string) bool {
+	if e.kind != k {
+		return false
+	}
+	switch {
+	case e.re != nil:
+		return e.re.MatchString(query)
+	case k == kindQuery || k == kindExec:
+		return sqlb.Compact(e.query) == sqlb.Compact(query)
+	}
+	return true
+}
+
+func (e *Expectation) String() string {
+	switch {
+	case e.re != nil:
+		return fmt.Sprintf("%s matching %q", e.kind, e.re)
+	case e.kind == kindQuery || e.kind == kindExec:
+		return fmt.Sprintf("%s %q", e.kind, sqlb.Compact(e.query))
+	}
+	return string(e.kind)
+}
+
+// Mock は期待する操作を登録し, ドライバへの呼び出しと照合します.
+type Mock struct {
+	mu         sync.Mutex
+	exps       []*Expectation
+	unordered  bool
+	unexpected []error
+}
+
+// ExpectQuery は SQL が query に一致する Query を期待します.
+//
+// SQL は sqlb.Compact で空白を正規化して比較します.
+func (m *Mock) ExpectQuery(query string) *Expectation {
+	return m.expect(&Expectation{kind: kindQuery, query: query})
+}
+
+// ExpectQueryRegexp は SQL が正規表現 expr に一致する Query を期待します.
+func (m *Mock) ExpectQueryRegexp(expr string) *Expectation {
+	return m.expect(&Expectation{kind: kindQuery, re: regexp.MustCompile(expr)})
+}
+
+// ExpectExec は SQL が query に一致する Exec を期待します.
+//
+// SQL は sqlb.Compact で空白を正規化して比較します.
+func (m *Mock) ExpectExec(query string) *Expectation {
+	return m.expect(&Expectation{kind: kindExec, query: query})
+}
+
+// ExpectExecRegexp は SQL が正規表現 expr に一致する Exec を期待します.
+func (m *Mock) ExpectExecRegexp(expr string) *Expectation {
+	return m.expect(&Expectation{kind: kindExec, re: regexp.MustCompile(expr)})
+}
+
+// ExpectBegin はトランザクションの開始を期待します.
+func (m *Mock) ExpectBegin() *Expectation {
+	return m.expect(&Expectation{kind: kindBegin})
+}
+
+// ExpectCommit はトランザクションのコミットを期待します.
+func (m *Mock) ExpectCommit() *Expectation {
+	return m.expect(&Expectation{kind: kindCommit})
+}
+
+// ExpectRollback はトランザクションのロールバックを期待します.
+func (m *Mock) ExpectRollback() *Expectation {
+	return m.expect(&Expectation{kind: kindRollback})
+}
+
+func (m *Mock) expect(e *Expectation) *Expectation {
+	m.mu.Lock()
+	m.exps = append(m.exps, e)
+	m.mu.Unlock()
+	return e
+}
+
+// MatchInOrder は期待を登録した順序で照合するかを設定します. 既定では順序どおりに照合します.
+func (m *Mock) MatchInOrder(ordered bool) {
+	m.mu.Lock()
+	m.unordered = !ordered
+	m.mu.Unlock()
+}
+
+// ExpectationsWereMet は期待しない操作がなく, すべての期待が満たされたかを検査します.
+func (m *Mock) ExpectationsWereMet() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.unexpected) > 0 {
+		return m.unexpected[0]
+	}
+	for _, e := range m.exps {
+		if !e.met {
+			return fmt.Errorf("sqltest: %s: %w", e, errNotMet)
+		}
+	}
+	return nil
+}
+
+// next は操作 k, SQL query に一致する期待を満たしたものとして返します.
+func (m *Mock) next(k kind, query string) (*Expectation, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, e := range m.exps {
+		if e.met {
+			continue
+		}
+		if e.match(k, query) {
+			e.met = true
+			return e, nil
+		}
+		if !m.unordered {
+			err := fmt.Errorf("sqltest: %s, next expectation is %s: %w", describe(k, query), e, errUnexpected)
+			m.unexpected = append(m.unexpected, err)
+			return nil, err
+		}
+	}
+	err := fmt.Errorf("sqltest: %s: %w", describe(k, query), errUnexpected)
+	m.unexpected = append(m.unexpected, err)
+	return nil, err
+}
+
+func describe(k kind, query string) string {
+	if k == kindQuery || k == kindExec {
+		return fmt.Sprintf("%s %q", k, sqlb.Compact(query))
+	}
+	return string(k)
+}
+
+// mocks は DSN ごとの Mock です.
+var (
+	mocks   sync.Map
+	mockSeq atomic.Int64
+)
+
+func init() {
+	sql.Register(DriverName, drv{})
+}
+
+// New は新しい Mock と, それに接続した *sql.DB を返します.
+func New() (*sql.DB, *Mock) {
+	m := &Mock{}
+	dsn := strconv.FormatInt(mockSeq.Add(1), 10)
+	mocks.Store(dsn, m)
+	db, err := sql.Open(DriverName, dsn)
+	if err != nil {
+		panic(err)
+	}
+	return db, m
+}
@@ -0,0 +1,186 @@
+package sqltest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/17e10/go-sqlb"
+	_ "github.com/17e10/go-sqlb/dialect/mysql"
+	"github.com/17e10/go-sqlb/sqlt"
+)
+
+type person struct {
+	Id   int64
+	Name string
+}
+
+func TestQuery(t *testing.T) {
+	db, mock := New()
+	defer db.Close()
+	ctx := context.TODO()
+
+	mock.ExpectQuery("SELECT `id`, `name`\n\tFROM `person`").
+		WillReturnRows([]string{"id", "name"}, []any{1, "Olivia"}, []any{2, "Kenny"})
+	mock.ExpectQueryRegexp("^SELECT COUNT").
+		WillReturnRows([]string{"count"}, []any{2})
+
+	rows, err := sqlb.Query(db, ctx, sqlb.T("SELECT # FROM `person`", sqlb.Columns((*person)(nil))))
+	if err != nil {
+		t.Fatalf("%s errored %v", "test Query", err)
+	}
+	var got []person
+	for rows.Next() {
+		var p person
+		if err := sqlb.Scan(rows, &p); err != nil {
+			t.Fatalf("%s Scan errored %v", "test Query", err)
+		}
+		got = append(got, p)
+	}
+	rows.Close()
+	if len(got) != 2 || got[0] != (person{1, "Olivia"}) || got[1] != (person{2, "Kenny"}) {
+		t.Errorf("%s = %+v", "test Query", got)
+	}
+
+	count, err := sqlb.ScanValue[int64](sqlb.QueryRow(db, ctx, sqlb.T("SELECT COUNT(*) FROM person")))
+	if err != nil || count != 2 {
+		t.Errorf("%s QueryRow = %d, %v, want %d", "test Query", count, err, 2)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("%s ExpectationsWereMet errored %v", "test Query", err)
+	}
+}
+
+func TestExec(t *testing.T) {
+	db, mock := New()
+	defer db.Close()
+	ctx := context.TODO()
+	errFailed := errors.New("failed")
+
+	mock.ExpectExec("INSERT INTO `person` (`name`) VALUES ('Olivia')").WillReturnResult(10, 1)
+	mock.ExpectExecRegexp("^DELETE").WillReturnError(errFailed)
+
+	res, err := sqlb.Exec(db, ctx, sqlb.Insert("person").Columns("name").Values("Olivia"))
+	if err != nil {
+		t.Fatalf("%s errored %v", "test Exec", err)
+	}
+	id, _ := res.LastInsertId()
+	n, _ := res.RowsAffected()
+	if id != 10 || n != 1 {
+		t.Errorf("%s result = %d, %d, want %d, %d", "test Exec", id, n, 10, 1)
+	}
+
+	_, err = sqlb.Exec(db, ctx, sqlb.T("DELETE FROM person"))
+	if !errors.Is(err, errFailed) {
+		t.Errorf("%s errored %v, want %v", "test Exec", err, errFailed)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("%s ExpectationsWereMet errored %v", "test Exec", err)
+	}
+}
+
+func TestTx(t *testing.T) {
+	db, mock := New()
+	defer db.Close()
+	ctx := context.TODO()
+	errFailed := errors.New("failed")
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE person SET age = 1")
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE person SET age = 2").WillReturnError(errFailed)
+	mock.ExpectRollback()
+
+	err := sqlb.InTx(ctx, db, nil, func(tx sqlt.Conn) error {
+		_, err := sqlb.Exec(tx, ctx, sqlb.T("UPDATE person SET age = 1"))
+		return err
+	})
+	if err != nil {
+		t.Errorf("%s errored %v", "test Tx", err)
+	}
+
+	err = sqlb.InTx(ctx, db, nil, func(tx sqlt.Conn) error {
+		_, err := sqlb.Exec(tx, ctx, sqlb.T("UPDATE person SET age = 2"))
+		return err
+	})
+	if !errors.Is(err, errFailed) {
+		t.Errorf("%s errored %v, want %v", "test Tx", err, errFailed)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("%s ExpectationsWereMet errored %v", "test Tx", err)
+	}
+}
+
+func TestOrder(t *testing.T) {
+	ctx := context.TODO()
+
+	tests := []struct {
+		ordered bool
+		err     error
+	}{
+		{true, errUnexpected},
+		{false, nil},
+	}
+
+	for _, te := range tests {
+		db, mock := New()
+		mock.MatchInOrder(te.ordered)
+		mock.ExpectExec("DELETE FROM a")
+		mock.ExpectExec("DELETE FROM b")
+
+		db.ExecContext(ctx, "DELETE FROM b")
+		db.ExecContext(ctx, "DELETE FROM a")
+		db.Close()
+
+		err := mock.ExpectationsWereMet()
+		if !errors.Is(err, te.err) {
+			t.Errorf("MatchInOrder(%t) errored %v, want %v", te.ordered, err, te.err)
+		}
+	}
+}
+
+func TestExpectationsWereMet(t *testing.T) {
+	db, mock := New()
+	defer db.Close()
+	ctx := context.TODO()
+
+	mock.ExpectQuery("SELECT 1")
+	err := mock.ExpectationsWereMet()
+	if !errors.Is(err, errNotMet) {
+		t.Errorf("%s errored %v, want %v", "test ExpectationsWereMet", err, errNotMet)
+	}
+
+	_, err = db.ExecContext(ctx, "SELECT 2")
+	if !errors.Is(err, errUnexpected) {
+		t.Errorf("%s Exec errored %v, want %v", "test ExpectationsWereMet", err, errUnexpected)
+	}
+	err = mock.ExpectationsWereMet()
+	if !errors.Is(err, errUnexpected) {
+		t.Errorf("%s errored %v, want %v", "test ExpectationsWereMet", err, errUnexpected)
+	}
+}
+
+func TestPrepare(t *testing.T) {
+	db, mock := New()
+	defer db.Close()
+	ctx := context.TODO()
+
+	mock.ExpectQuery("SELECT name FROM person").WillReturnRows([]string{"name"}, []any{"Olivia"})
+
+	st, err := db.PrepareContext(ctx, "SELECT name FROM person")
+	if err != nil {
+		t.Fatalf("%s errored %v", "test Prepare", err)
+	}
+	defer st.Close()
+	var name string
+	if err := st.QueryRowContext(ctx).Scan(&name); err != nil || name != "Olivia" {
+		t.Errorf("%s = %q, %v, want %q", "test Prepare", name, err, "Olivia")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("%s ExpectationsWereMet errored %v", "test Prepare", err)
+	}
+}
@@ -0,0 +1,92 @@
+package sqlb
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// ErrUntrustedSqler は厳格モードでパッケージが生成していない Sqler を展開したときのエラーです.
+var ErrUntrustedSqler = errors.New("untrusted sqler")
+
+// strict は厳格モードが有効かを保持します.
+var strict atomic.Bool
+
+// SetStrict は厳格モードを設定します.
+//
+// 厳格モードでは $ や And, Where などで展開する Sqler を
+// T, M, ビルダーなどこのパッケージが生成したものと Raw, Unsafe で包んだものに制限します.
+// StringSqler, SqlerFunc や独自に実装した Sqler は ErrUntrustedSqler になります.
+// fmt.Sprintf などで組み立てた SQL が紛れ込むことを防ぎます.
+func SetStrict(enable bool) {
+	strict.Store(enable)
+}
+
+// GetStrict は厳格モードが有効かを返します.
+func GetStrict() bool {
+	return strict.Load()
+}
+
+// trustedSqler はこのパッケージが生成した Sqler です.
+type trustedSqler interface {
+	Sqler
+	trusted()
+}
+
+func (*texec) trusted()         {}
+func (*mexec) trusted()         {}
+func (*cond) trusted()          {}
+func (*Compound) trusted()      {}
+func (*SelectBuilder) trusted() {}
+func (*InsertBuilder) trusted() {}
+func (*UpdateBuilder) trusted() {}
+func (*DeleteBuilder) trusted() {}
+func (*UpsertBuilder) trusted() {}
+func (sqlerFunc) trusted()      {}
+func (unsafeSqler) trusted()    {}
+
+// sqlerFunc はこのパッケージが関数から生成する Sqler です.
+type sqlerFunc func(w Writer) error
+
+// Sql は fn(w) を呼び出します.
+func (fn sqlerFunc) Sql(w Writer) error {
+	return fn(w)
+}
+
+// unsafeSqler は Unsafe で包んだ Sqler です.
+type unsafeSqler struct {
+	Sqler
+}
+
+// Unsafe は sqler を厳格モードでも展開できるようにします.
+//
+// sqler に利用者の入力が含まれないことを呼び出し側で保証してください.
+func Unsafe(sqler Sqler) Sqler {
+	return unsafeSqler{sqler}
+}
+
+// Raw は SQL 文字列 s を厳格モードでも展開できるようにします.
+//
+// Raw は Unsafe(StringSqler(s)) と同じです.
+func Raw(s string) Sqler {
+	return Unsafe(StringSqler(s))
+}
+
+// checkSqler は厳格モードで sqler が展開できるかを検査します.
+func checkSqler(sqler Sqler) error {
+	if !strict.Load() {
+		return nil
+	}
+	if _, ok := sqler.(trustedSqler); !ok {
+		return fmt.Errorf("got type %T: %w", sqler, ErrUntrustedSqler)
+	}
+	return nil
+}
+
+// writeSqler は厳格モードを検査して sqler を展開します.
+func writeSqler(w Writer, sqler Sqler) error {
+	if err := checkSqler(sqler); err != nil {
+		return err
+	}
+	return sqler.Sql(w)
+}
@@ -0,0 +1,76 @@
+package sqlb
+
+import (
+	"errors"
+	"testing"
+)
+
+func useStrict(t *testing.T) {
+	t.Helper()
+	SetStrict(true)
+	t.Cleanup(func() { SetStrict(false) })
+}
+
+func TestStrict(t *testing.T) {
+	useStrict(t)
+
+	user := StringSqler("id = 1")
+	fn := SqlerFunc(func(w Writer) error {
+		w.WriteString("id = 1")
+		return nil
+	})
+
+	tests := []struct {
+		src  Sqler
+		want string
+		err  bool
+	}{
+		// パッケージが生成した Sqler
+		{T("SELECT * FROM t WHERE $", T("#", "id")), "SELECT * FROM t WHERE `id`", false},
+		{M("SELECT * FROM t WHERE $c", map[string]any{"$c": Eq("id", 1)}), "SELECT * FROM t WHERE `id` = 1", false},
+		{Select("id").From("t").Where(And(Eq("id", 1), Not(IsNull("name")))), "SELECT `id` FROM `t` WHERE `id` = 1 AND NOT (`name` IS NULL)", false},
+		{Union(Select("id").From("a"), Select("id").From("b")), "SELECT `id` FROM `a` UNION SELECT `id` FROM `b`", false},
+
+		// Raw, Unsafe
+		{T("SELECT * FROM t WHERE $", Raw("id = 1")), "SELECT * FROM t WHERE id = 1", false},
+		{T("SELECT * FROM t WHERE $", Unsafe(fn)), "SELECT * FROM t WHERE id = 1", false},
+		{Raw("SELECT 1"), "SELECT 1", false},
+
+		// 信頼できない Sqler
+		{user, "", true},
+		{T("SELECT * FROM t WHERE $", user), "", true},
+		{T("SELECT * FROM t WHERE $", fn), "", true},
+		{T("SELECT * FROM t WHERE $", T("$", user)), "", true},
+		{Select("id").From("t").Where(user), "", true},
+		{Select("id").From("t").Where(Or(Eq("id", 1), user)), "", true},
+		{Bracket(user), "", true},
+		{Not(user), "", true},
+		{Eq("id", user), "", true},
+		{Select(user).From("t"), "", true},
+	}
+
+	for i, te := range tests {
+		got, err := Stringify(te.src)
+		if te.err {
+			if !errors.Is(err, ErrUntrustedSqler) {
+				t.Errorf("test Strict #%d errored %v, want %v", i, err, ErrUntrustedSqler)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("test Strict #%d errored %v", i, err)
+			continue
+		}
+		if got != te.want {
+			t.Errorf("test Strict #%d = %q, want %q", i, got, te.want)
+		}
+	}
+}
+
+func TestStrictDisabled(t *testing.T) {
+	got, err := Stringify(T("SELECT * FROM t WHERE $", StringSqler("id = 1")))
+	want := "SELECT * FROM t WHERE id = 1"
+	if err != nil || got != want {
+		t.Errorf("%s = %q, %v, want %q", "test StrictDisabled", got, err, want)
+	}
+}
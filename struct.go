@@ -2,33 +2,84 @@ package sqlb
 
 import (
 	"reflect"
+	"strings"
 	"sync"
 )
 
 type columnInfo struct {
-	name  string
-	index []int
+	name      string
+	index     []int
+	nullzero  bool
+	pk        bool
+	auto      bool
+	sensitive bool
+}
+
+// parseTag は sqlb タグを名前とオプションに分解します.
+//
+//	`sqlb:"nickname,nullzero"`
+//	`sqlb:"id,pk,auto"`
+//
+// オプションには次のものがあります.
+//
+//	nullzero	ゼロ値を NULL として扱う
+//	pk			主キー
+//	auto		AUTO_INCREMENT などデータベースが値を生成するカラム
+//	sensitive	StringifyRedacted で値を伏せる
+func parseTag(tag string) (name string, opts map[string]bool) {
+	name, rest, _ := strings.Cut(tag, ",")
+	opts = make(map[string]bool)
+	for rest != "" {
+		var opt string
+		opt, rest, _ = strings.Cut(rest, ",")
+		opts[strings.TrimSpace(opt)] = true
+	}
+	return strings.TrimSpace(name), opts
+}
+
+// value は構造体 rv からカラムの値を取り出します.
+//
+// nullzero オプションを持つカラムはゼロ値を nil に, ポインタを参照先の値にします.
+// sensitive オプションを持つカラムは値を Sensitive で包みます.
+func (c columnInfo) value(rv reflect.Value) any {
+	fv := rv.FieldByIndex(c.index)
+	if c.nullzero {
+		if fv.IsZero() {
+			return nil
+		}
+		if fv.Kind() == reflect.Pointer {
+			fv = fv.Elem()
+		}
+	}
+	if c.sensitive {
+		return Sensitive(fv.Interface())
+	}
+	return fv.Interface()
 }
 
 // getColumnInfos の結果キャッシュ
-var cicache = make(map[string][]columnInfo)
+var cicache = make(map[reflect.Type][]columnInfo)
 
 // getColumnInfos を排他制御する mutex
 var cimu = sync.Mutex{}
 
 // getColumnInfos は構造体の exported なカラム情報を返します.
 func getColumnInfos[V any](v *V) []columnInfo {
-	var cols []columnInfo
-
-	cimu.Lock()
-	defer cimu.Unlock()
-
 	rt := reflect.TypeOf(v).Elem()
 	if rt.Kind() != reflect.Struct {
 		panic(errNoStruct)
 	}
+	return typeColumnInfos(rt)
+}
 
-	key := rt.String()
+// typeColumnInfos は構造体型 rt の exported なカラム情報を返します.
+func typeColumnInfos(rt reflect.Type) []columnInfo {
+	var cols []columnInfo
+
+	cimu.Lock()
+	defer cimu.Unlock()
+
+	key := rt
 	if cols = cicache[key]; cols != nil {
 		return cols
 	}
@@ -39,11 +90,18 @@ func getColumnInfos[V any](v *V) []columnInfo {
 		if f.Anonymous || !f.IsExported() {
 			continue
 		}
-		name := f.Tag.Get("sqlb")
+		name, opts := parseTag(f.Tag.Get("sqlb"))
 		if name == "" {
 			name = columnName(f.Name)
 		}
-		cols = append(cols, columnInfo{name, f.Index})
+		cols = append(cols, columnInfo{
+			name:      name,
+			index:     f.Index,
+			nullzero:  opts["nullzero"],
+			pk:        opts["pk"],
+			auto:      opts["auto"],
+			sensitive: opts["sensitive"],
+		})
 	}
 	cicache[key] = cols
 	return cols
@@ -51,7 +109,11 @@ func getColumnInfos[V any](v *V) []columnInfo {
 
 // exportedColumns は構造体の exported なカラムから excludes を除外したカラム情報を返します.
 func exportedColumns[V any](v *V, excludes []string) []columnInfo {
-	cols := getColumnInfos(v)
+	return excludeColumns(getColumnInfos(v), excludes)
+}
+
+// excludeColumns はカラム情報から excludes を除外します.
+func excludeColumns(cols []columnInfo, excludes []string) []columnInfo {
 	if len(excludes) == 0 {
 		return cols
 	}
@@ -86,12 +148,13 @@ func Columns[V any](v *V, excludes ...string) []string {
 // Values は構造体から値リストを作成します.
 //
 // excludes でリストから除外するカラムを指定できます.
+// nullzero オプションを持つフィールドはゼロ値のとき nil (NULL) になります.
 func Values[V any](v *V, excludes ...string) []any {
 	cols := exportedColumns(v, excludes)
 	rv := reflect.ValueOf(v).Elem()
 	r := make([]any, len(cols))
 	for i, c := range cols {
-		r[i] = rv.FieldByIndex(c.index).Interface()
+		r[i] = c.value(rv)
 	}
 	return r
 }
@@ -106,7 +169,7 @@ func GroupValues[V any](v []V, excludes ...string) [][]any {
 		rv := reflect.ValueOf(&val).Elem()
 		r := make([]any, len(cols))
 		for i, c := range cols {
-			r[i] = rv.FieldByIndex(c.index).Interface()
+			r[i] = c.value(rv)
 		}
 		group[i] = r
 	}
@@ -122,7 +185,7 @@ func KeyValues[V any](v *V, excludes ...string) []Kv {
 	r := make([]Kv, len(cols))
 	for i, c := range cols {
 		r[i].K = c.name
-		r[i].V = rv.FieldByIndex(c.index).Interface()
+		r[i].V = c.value(rv)
 	}
 	return r
 }
@@ -45,6 +45,33 @@ func TestValues(t *testing.T) {
 	}
 }
 
+func TestValuesNullzero(t *testing.T) {
+	type data struct {
+		Id       int64
+		Nickname string  `sqlb:"nickname,nullzero"`
+		Memo     *string `sqlb:",nullzero"`
+	}
+
+	got := Columns((*data)(nil))
+	want := []string{"id", "nickname", "memo"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("%s = %v, want %v", "test Values nullzero #1", got, want)
+	}
+
+	vals := Values(&data{})
+	wantVals := []any{int64(0), nil, nil}
+	if !reflect.DeepEqual(vals, wantVals) {
+		t.Errorf("%s = %v, want %v", "test Values nullzero #2", vals, wantVals)
+	}
+
+	memo := "memo"
+	vals = Values(&data{1, "kenny", &memo})
+	wantVals = []any{int64(1), "kenny", "memo"}
+	if !reflect.DeepEqual(vals, wantVals) {
+		t.Errorf("%s = %v, want %v", "test Values nullzero #3", vals, wantVals)
+	}
+}
+
 func TestGroupValues(t *testing.T) {
 	got := GroupValues(persons)
 	want := [][]any{
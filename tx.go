@@ -0,0 +1,118 @@
+package sqlb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/17e10/go-sqlb/sqlt"
+)
+
+// txConn は InTx が fn に渡すトランザクションです.
+type txConn struct {
+	sqlt.Tx
+	depth int
+}
+
+// InTx は fn をトランザクションの中で実行します.
+//
+// fn が nil を返すとコミットし, エラーを返すかパニックするとロールバックします.
+// conn が *sql.DB や HookedConn のように BeginTx を持つときは opts でトランザクションを開始します.
+//
+// conn がトランザクション (fn が受け取った tx や *sql.Tx) のときは
+// セーブポイントを使って入れ子のトランザクションにします.
+// このとき fn が失敗するとセーブポイントまでロールバックし, opts は無視します.
+//
+//	err := sqlb.InTx(ctx, db, nil, func(tx sqlt.Conn) error {
+//		if _, err := sqlb.Exec(tx, ctx, q1); err != nil {
+//			return err
+//		}
+//		return sqlb.InTx(ctx, tx, nil, func(tx sqlt.Conn) error {
+//			_, err := sqlb.Exec(tx, ctx, q2)
+//			return err
+//		})
+//	})
+func InTx(ctx context.Context, conn sqlt.Conn, opts *sql.TxOptions, fn func(tx sqlt.Conn) error) error {
+	switch c := conn.(type) {
+	case *txConn:
+		return inSavepoint(ctx, c.Tx, c.depth+1, fn)
+	case sqlt.Tx:
+		return inSavepoint(ctx, c, 1, fn)
+	}
+	tx, err := beginTx(ctx, conn, opts)
+	if err != nil {
+		return err
+	}
+	return inTx(tx, fn)
+}
+
+// beginTx は conn の BeginTx でトランザクションを開始します.
+func beginTx(ctx context.Context, conn any, opts *sql.TxOptions) (sqlt.Tx, error) {
+	switch c := conn.(type) {
+	case sqlt.Beginner:
+		tx, err := c.BeginTx(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		return tx, nil
+	case sqlt.TxBeginner:
+		return c.BeginTx(ctx, opts)
+	}
+	return nil, errNoBeginner
+}
+
+// inTx は fn を tx の中で実行し, コミットまたはロールバックします.
+func inTx(tx sqlt.Tx, fn func(tx sqlt.Conn) error) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err = fn(&txConn{tx, 0}); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// inSavepoint は fn をセーブポイントの中で実行し, 解放またはロールバックします.
+func inSavepoint(ctx context.Context, tx sqlt.Tx, depth int, fn func(tx sqlt.Conn) error) (err error) {
+	name := fmt.Sprintf("sqlb_sp%d", depth)
+	d := dialect()
+	if err = execSavepoint(ctx, tx, d.WriteSavepoint, name); err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			execSavepoint(ctx, tx, d.WriteRollbackTo, name)
+			panic(p)
+		}
+	}()
+
+	if err = fn(&txConn{tx, depth}); err != nil {
+		if rerr := execSavepoint(ctx, tx, d.WriteRollbackTo, name); rerr != nil {
+			return fmt.Errorf("%w (rollback to savepoint: %v)", err, rerr)
+		}
+		return err
+	}
+	return execSavepoint(ctx, tx, d.WriteReleaseSavepoint, name)
+}
+
+// execSavepoint は write で書き込んだセーブポイントの SQL を実行します.
+//
+// write が何も書き込まなければ何もしません.
+func execSavepoint(ctx context.Context, tx sqlt.Execer, write func(w Writer, name string) error, name string) error {
+	w := &strings.Builder{}
+	if err := write(w, name); err != nil {
+		return err
+	}
+	if w.Len() == 0 {
+		return nil
+	}
+	_, err := tx.ExecContext(ctx, w.String())
+	return err
+}
@@ -0,0 +1,113 @@
+package sqlb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/17e10/go-sqlb/sqlt"
+)
+
+// testTx は Exec した SQL とコミット, ロールバックを記録する sqlt.Tx です.
+type testTx struct {
+	sqlt.TestExecer
+	log []string
+}
+
+func (tx *testTx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	tx.log = append(tx.log, query)
+	return tx.TestExecer.ExecContext(ctx, query, args...)
+}
+
+func (tx *testTx) PrepareContext(context.Context, string) (*sql.Stmt, error) {
+	return nil, errors.New("not supported")
+}
+
+func (tx *testTx) QueryContext(context.Context, string, ...any) (*sql.Rows, error) {
+	return nil, errors.New("not supported")
+}
+
+func (tx *testTx) QueryRowContext(context.Context, string, ...any) *sql.Row {
+	return nil
+}
+
+func (tx *testTx) Commit() error {
+	tx.log = append(tx.log, "COMMIT")
+	return nil
+}
+
+func (tx *testTx) Rollback() error {
+	tx.log = append(tx.log, "ROLLBACK")
+	return nil
+}
+
+func TestInTxSavepoint(t *testing.T) {
+	ctx := context.TODO()
+	errFailed := errors.New("failed")
+
+	tx := &testTx{}
+	err := InTx(ctx, tx, nil, func(tx sqlt.Conn) error {
+		Exec(tx, ctx, T("a"))
+		InTx(ctx, tx, nil, func(tx sqlt.Conn) error {
+			Exec(tx, ctx, T("b"))
+			return errFailed
+		})
+		return InTx(ctx, tx, nil, func(tx sqlt.Conn) error {
+			_, err := Exec(tx, ctx, T("c"))
+			return err
+		})
+	})
+	want := []string{
+		"SAVEPOINT `sqlb_sp1`",
+		"a",
+		"SAVEPOINT `sqlb_sp2`",
+		"b",
+		"ROLLBACK TO SAVEPOINT `sqlb_sp2`",
+		"SAVEPOINT `sqlb_sp2`",
+		"c",
+		"RELEASE SAVEPOINT `sqlb_sp2`",
+		"RELEASE SAVEPOINT `sqlb_sp1`",
+	}
+	if err != nil || !reflect.DeepEqual(tx.log, want) {
+		t.Errorf("%s = %q, %v, want %q", "test InTx savepoint", tx.log, err, want)
+	}
+}
+
+func TestInTxPanic(t *testing.T) {
+	ctx := context.TODO()
+	tx := &testTx{}
+
+	func() {
+		defer func() {
+			if p := recover(); p != "boom" {
+				t.Errorf("%s recovered %v, want %v", "test InTx panic", p, "boom")
+			}
+		}()
+		InTx(ctx, tx, nil, func(tx sqlt.Conn) error {
+			panic("boom")
+		})
+	}()
+
+	want := []string{"SAVEPOINT `sqlb_sp1`", "ROLLBACK TO SAVEPOINT `sqlb_sp1`"}
+	if !reflect.DeepEqual(tx.log, want) {
+		t.Errorf("%s = %q, want %q", "test InTx panic", tx.log, want)
+	}
+}
+
+func TestInTxCommit(t *testing.T) {
+	tx := &testTx{}
+	errFailed := errors.New("failed")
+
+	if err := inTx(tx, func(sqlt.Conn) error { return nil }); err != nil {
+		t.Errorf("%s errored %v", "test inTx commit", err)
+	}
+	if err := inTx(tx, func(sqlt.Conn) error { return errFailed }); err != errFailed {
+		t.Errorf("%s errored %v, want %v", "test inTx rollback", err, errFailed)
+	}
+	want := []string{"COMMIT", "ROLLBACK"}
+	if !reflect.DeepEqual(tx.log, want) {
+		t.Errorf("%s = %q, want %q", "test inTx", tx.log, want)
+	}
+}
@@ -0,0 +1,200 @@
+package sqlb
+
+import "fmt"
+
+// FullTableError は WHERE 条件のない UPDATE, DELETE 文を展開しようとしたときのエラーです.
+//
+// And() のように常に TRUE になる条件式も WHERE 条件のないものとして扱います.
+// テーブル全体を更新, 削除するには AllowFullTable を呼び出します.
+type FullTableError struct {
+	Verb  string
+	Table string
+}
+
+func (e *FullTableError) Error() string {
+	return fmt.Sprintf("%s %s without WHERE condition", e.Verb, e.Table)
+}
+
+// UpdateBuilder は UPDATE 文を組み立てる Sqler です.
+//
+// UpdateBuilder は SelectBuilder と同様に不変です.
+//
+//	q := sqlb.Update("person").
+//		Set(sqlb.KeyValues(&p, "id")...).
+//		Where(sqlb.T("id = @", p.Id))
+type UpdateBuilder struct {
+	table     string
+	sets      []Sqler
+	where     []Sqler
+	all       bool
+	returning []string
+}
+
+// Update は table を更新する UpdateBuilder を作成します.
+func Update(table string) *UpdateBuilder {
+	return &UpdateBuilder{table: table}
+}
+
+// Set は `key` = value の代入を追加します.
+//
+// 構造体から代入するには KeyValues を使います.
+func (b *UpdateBuilder) Set(kv ...Kv) *UpdateBuilder {
+	sets := make([]Sqler, len(kv))
+	for i, v := range kv {
+		sets[i] = T("@", []Kv{v})
+	}
+	return b.SetExpr(sets...)
+}
+
+// SetExpr は count = count + 1 のような代入式を追加します.
+func (b *UpdateBuilder) SetExpr(exprs ...Sqler) *UpdateBuilder {
+	c := *b
+	c.sets = appendClone(c.sets, exprs...)
+	return &c
+}
+
+// Where は WHERE 句の条件を追加します.
+//
+// 複数の条件は AND で繋げます.
+func (b *UpdateBuilder) Where(conds ...Sqler) *UpdateBuilder {
+	c := *b
+	c.where = appendClone(c.where, conds...)
+	return &c
+}
+
+// AllowFullTable は WHERE 条件なしでテーブル全体を更新することを許可します.
+func (b *UpdateBuilder) AllowFullTable() *UpdateBuilder {
+	c := *b
+	c.all = true
+	return &c
+}
+
+// Returning は更新した行の columns を返すようにします.
+//
+// PostgreSQL などでは RETURNING 句に, SQL Server では OUTPUT INSERTED 句になります.
+// columns を省略するとすべてのカラムを返します.
+func (b *UpdateBuilder) Returning(columns ...string) *UpdateBuilder {
+	c := *b
+	c.returning = append([]string{}, columns...)
+	return &c
+}
+
+// Sql は UPDATE 文を展開します.
+//
+// WHERE 条件がなく AllowFullTable も呼び出していないときは *FullTableError を返します.
+func (b *UpdateBuilder) Sql(w Writer) error {
+	if err := b.sql(w); err != nil {
+		return fmt.Errorf("update: %w", err)
+	}
+	return nil
+}
+
+func (b *UpdateBuilder) sql(w Writer) error {
+	if !hasWhere(b.where) && !b.all {
+		return &FullTableError{"UPDATE", b.table}
+	}
+	if len(b.sets) == 0 {
+		return errEmptySlice
+	}
+
+	w.WriteString("UPDATE ")
+	if err := writeIdent(w, b.table); err != nil {
+		return err
+	}
+	w.WriteString(" SET ")
+	if err := joinSqler(", ", b.sets).Sql(w); err != nil {
+		return err
+	}
+	if err := putOutput(w, b.returning, "INSERTED."); err != nil {
+		return err
+	}
+	if err := putWhere(w, b.where); err != nil {
+		return err
+	}
+	return putReturning(w, b.returning)
+}
+
+// DeleteBuilder は DELETE 文を組み立てる Sqler です.
+//
+// DeleteBuilder は SelectBuilder と同様に不変です.
+type DeleteBuilder struct {
+	table     string
+	where     []Sqler
+	all       bool
+	returning []string
+}
+
+// Delete は table から削除する DeleteBuilder を作成します.
+func Delete(table string) *DeleteBuilder {
+	return &DeleteBuilder{table: table}
+}
+
+// Where は WHERE 句の条件を追加します.
+//
+// 複数の条件は AND で繋げます.
+func (b *DeleteBuilder) Where(conds ...Sqler) *DeleteBuilder {
+	c := *b
+	c.where = appendClone(c.where, conds...)
+	return &c
+}
+
+// AllowFullTable は WHERE 条件なしでテーブル全体を削除することを許可します.
+func (b *DeleteBuilder) AllowFullTable() *DeleteBuilder {
+	c := *b
+	c.all = true
+	return &c
+}
+
+// Returning は削除した行の columns を返すようにします.
+//
+// PostgreSQL などでは RETURNING 句に, SQL Server では OUTPUT DELETED 句になります.
+// columns を省略するとすべてのカラムを返します.
+func (b *DeleteBuilder) Returning(columns ...string) *DeleteBuilder {
+	c := *b
+	c.returning = append([]string{}, columns...)
+	return &c
+}
+
+// Sql は DELETE 文を展開します.
+//
+// WHERE 条件がなく AllowFullTable も呼び出していないときは *FullTableError を返します.
+func (b *DeleteBuilder) Sql(w Writer) error {
+	if err := b.sql(w); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+	return nil
+}
+
+func (b *DeleteBuilder) sql(w Writer) error {
+	if !hasWhere(b.where) && !b.all {
+		return &FullTableError{"DELETE", b.table}
+	}
+
+	w.WriteString("DELETE FROM ")
+	if err := writeIdent(w, b.table); err != nil {
+		return err
+	}
+	if err := putOutput(w, b.returning, "DELETED."); err != nil {
+		return err
+	}
+	if err := putWhere(w, b.where); err != nil {
+		return err
+	}
+	return putReturning(w, b.returning)
+}
+
+// hasWhere は where が行を絞り込む条件を持つかを返します.
+func hasWhere(where []Sqler) bool {
+	return len(where) > 0 && !alwaysTrue(And(where...))
+}
+
+// putWhere は条件があれば WHERE 句を展開します.
+//
+// And() のように常に TRUE になる条件は展開しません.
+func putWhere(w Writer, where []Sqler) error {
+	if !hasWhere(where) {
+		return nil
+	}
+	w.WriteString(" WHERE ")
+	return And(where...).Sql(w)
+}
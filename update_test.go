@@ -0,0 +1,67 @@
+package sqlb
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestUpdate(t *testing.T) {
+	tests := []struct {
+		sqler Sqler
+		want  string
+		err   string
+	}{
+		{Update("person").Set(KeyValues(&olivia, "id", "age")...).Where(T("id = @", olivia.Id)),
+			"UPDATE `person` SET `family_name` = 'Williams', `given_name` = 'Olivia' WHERE id = 1", ""},
+		{Update("person").Set(Kv{"age", 55}).SetExpr(T("count = count + 1")).Where(T("id = @", 1), T("age < @", 55)),
+			"UPDATE `person` SET `age` = 55, count = count + 1 WHERE id = 1 AND age < 55", ""},
+		{Update("person").Set(Kv{"age", 0}).AllowFullTable(),
+			"UPDATE `person` SET `age` = 0", ""},
+		{Update("person").Set(Kv{"age", 0}), "", "update: UPDATE person without WHERE condition"},
+		{Update("person").Where(T("id = @", 1)), "", "update: empty array or slice"},
+		{Delete("person").Where(T("id = @", 1)),
+			"DELETE FROM `person` WHERE id = 1", ""},
+		{Delete("person").AllowFullTable(),
+			"DELETE FROM `person`", ""},
+		{Delete("person"), "", "delete: DELETE person without WHERE condition"},
+
+		// 空の条件式は WHERE 条件なしとして扱う
+		{Delete("person").Where(And()), "", "delete: DELETE person without WHERE condition"},
+		{Delete("person").Where(And(And(), And())), "", "delete: DELETE person without WHERE condition"},
+		{Delete("person").Where(Or(And(), T("id = 1"))), "", "delete: DELETE person without WHERE condition"},
+		{Update("person").Set(Kv{"age", 0}).Where(And()), "", "update: UPDATE person without WHERE condition"},
+		{Delete("person").Where(And()).AllowFullTable(), "DELETE FROM `person`", ""},
+		{Update("person").Set(Kv{"age", 0}).Where(And(), T("id = 1")),
+			"UPDATE `person` SET `age` = 0 WHERE TRUE AND id = 1", ""},
+		{Delete("person").Where(Or()), "DELETE FROM `person` WHERE FALSE", ""},
+	}
+
+	for i, te := range tests {
+		var goterr string
+
+		name := fmt.Sprintf("test Update #%d", i)
+		got, err := Stringify(te.sqler)
+		if err != nil {
+			goterr = err.Error()
+		}
+		if goterr != te.err {
+			t.Errorf("%s errored %q, want %q", name, goterr, te.err)
+		}
+		if got != te.want {
+			t.Errorf("%s returned %q, want %q", name, got, te.want)
+		}
+	}
+}
+
+func TestFullTableError(t *testing.T) {
+	_, err := Stringify(Delete("person"))
+
+	var fte *FullTableError
+	if !errors.As(err, &fte) {
+		t.Fatalf("%s errored %v, want *FullTableError", "test FullTableError", err)
+	}
+	if fte.Verb != "DELETE" || fte.Table != "person" {
+		t.Errorf("%s = %+v", "test FullTableError", fte)
+	}
+}
@@ -0,0 +1,249 @@
+package sqlb
+
+import (
+	"fmt"
+
+	d "github.com/17e10/go-sqlb/dialect"
+)
+
+// UpsertBuilder は行を挿入し, キーが重複するときは更新する UPSERT 文を組み立てる Sqler です.
+//
+// 構文は dialect に応じて次のようになります.
+//
+//	MySQL				INSERT ... ON DUPLICATE KEY UPDATE col = VALUES(col)
+//	MySQL 8.0.20 以降	INSERT ... AS new ON DUPLICATE KEY UPDATE col = new.col
+//	PostgreSQL, SQLite	INSERT ... ON CONFLICT (key) DO UPDATE SET col = EXCLUDED.col
+//	SQL Server			MERGE INTO ... USING (VALUES ...) ...
+//
+// UpsertBuilder は SelectBuilder と同様に不変です.
+type UpsertBuilder struct {
+	ins     *InsertBuilder
+	keys    []string
+	updates []string
+}
+
+// Upsert は table に UPSERT する UpsertBuilder を作成します.
+//
+// keys は重複を判定するキーのカラムです.
+func Upsert(table string, keys ...string) *UpsertBuilder {
+	return &UpsertBuilder{ins: Insert(table), keys: keys}
+}
+
+// Set は Key-Value リストを 1 行追加します.
+func (b *UpsertBuilder) Set(kv ...Kv) *UpsertBuilder {
+	row := insertRow{make([]string, len(kv)), make([]any, len(kv))}
+	for i, v := range kv {
+		row.columns[i], row.values[i] = v.K, v.V
+	}
+	c := *b
+	c.ins = b.ins.Columns(row.columns...).Values(row.values...)
+	return &c
+}
+
+// Struct は構造体 v を 1 行追加します.
+//
+// excludes で除外するカラムを指定できます.
+func (b *UpsertBuilder) Struct(v any, excludes ...string) *UpsertBuilder {
+	c := *b
+	c.ins = b.ins.Struct(v, excludes...)
+	return &c
+}
+
+// Structs は構造体の配列 v を行として追加します.
+//
+// excludes で除外するカラムを指定できます.
+func (b *UpsertBuilder) Structs(v any, excludes ...string) *UpsertBuilder {
+	c := *b
+	c.ins = b.ins.Structs(v, excludes...)
+	return &c
+}
+
+// Update はキーが重複するときに更新するカラムを指定します.
+//
+// 呼び出さないときはキー以外のすべてのカラムを更新します.
+// 引数なしで呼び出すと重複した行を更新しません.
+func (b *UpsertBuilder) Update(columns ...string) *UpsertBuilder {
+	c := *b
+	c.updates = append([]string{}, columns...)
+	return &c
+}
+
+// Sql は UPSERT 文を展開します.
+func (b *UpsertBuilder) Sql(w Writer) error {
+	if err := b.sql(w); err != nil {
+		return fmt.Errorf("upsert: %w", err)
+	}
+	return nil
+}
+
+func (b *UpsertBuilder) sql(w Writer) error {
+	if len(b.keys) == 0 {
+		return fmt.Errorf("keys: %w", errEmptySlice)
+	}
+	if b.ins.err != nil {
+		return b.ins.err
+	}
+	cols, err := b.ins.validate()
+	if err != nil {
+		return err
+	}
+	updates, err := b.updateColumns(cols)
+	if err != nil {
+		return err
+	}
+
+	style := dialect().UpsertStyle()
+	if style == d.UpsertMerge {
+		return b.writeMerge(w, cols, updates)
+	}
+
+	if _, err := b.ins.writeInsert(w, "INSERT"); err != nil {
+		return err
+	}
+	switch style {
+	case d.UpsertOnDuplicateKey:
+		return writeUpdates(w, " ON DUPLICATE KEY UPDATE ", updates, b.keys[:1], "VALUES(", ")")
+	case d.UpsertOnDuplicateKeyAlias:
+		w.WriteString(" AS new")
+		return writeUpdates(w, " ON DUPLICATE KEY UPDATE ", updates, b.keys[:1], "new.", "")
+	case d.UpsertOnConflict:
+		w.WriteString(" ON CONFLICT (")
+		if err := putIdentList(w, b.keys); err != nil {
+			return err
+		}
+		if len(updates) == 0 {
+			w.WriteString(") DO NOTHING")
+			return nil
+		}
+		return writeUpdates(w, ") DO UPDATE SET ", updates, nil, "EXCLUDED.", "")
+	}
+	return fmt.Errorf("upsert style %d: %w", style, errNoValueType)
+}
+
+// updateColumns は更新するカラムを返します.
+func (b *UpsertBuilder) updateColumns(cols []string) ([]string, error) {
+	has := make(map[string]bool, len(cols))
+	for _, c := range cols {
+		has[c] = true
+	}
+	for _, k := range b.keys {
+		if !has[k] {
+			return nil, fmt.Errorf("key %s: %w", k, errNoSuchKey)
+		}
+	}
+	if b.updates != nil {
+		for _, u := range b.updates {
+			if !has[u] {
+				return nil, fmt.Errorf("update %s: %w", u, errNoSuchKey)
+			}
+		}
+		return b.updates, nil
+	}
+
+	isKey := make(map[string]bool, len(b.keys))
+	for _, k := range b.keys {
+		isKey[k] = true
+	}
+	updates := make([]string, 0, len(cols))
+	for _, c := range cols {
+		if !isKey[c] {
+			updates = append(updates, c)
+		}
+	}
+	return updates, nil
+}
+
+// writeUpdates は prefix に続けて `col` = ref(col), ... を展開します.
+//
+// ref(col) は before, 識別子 col, after を連結したものです.
+// updates が空のときは noop のカラムを自分自身で更新します.
+func writeUpdates(w Writer, prefix string, updates, noop []string, before, after string) error {
+	if len(updates) == 0 {
+		updates, before, after = noop, "", ""
+	}
+	w.WriteString(prefix)
+	for i, c := range updates {
+		if i > 0 {
+			w.WriteString(", ")
+		}
+		if err := writeIdent(w, c); err != nil {
+			return err
+		}
+		w.WriteString(" = ")
+		w.WriteString(before)
+		if err := writeIdent(w, c); err != nil {
+			return err
+		}
+		w.WriteString(after)
+	}
+	return nil
+}
+
+// writeMerge は MERGE 文を展開します.
+func (b *UpsertBuilder) writeMerge(w Writer, cols, updates []string) error {
+	if len(cols) == 0 {
+		return fmt.Errorf("columns: %w", errEmptySlice)
+	}
+
+	w.WriteString("MERGE INTO ")
+	if err := writeIdent(w, b.ins.table); err != nil {
+		return err
+	}
+	w.WriteString(" AS target USING (VALUES ")
+	if err := b.ins.writeRows(w); err != nil {
+		return err
+	}
+	w.WriteString(") AS source (")
+	if err := putIdentList(w, cols); err != nil {
+		return err
+	}
+	w.WriteString(") ON ")
+	for i, k := range b.keys {
+		if i > 0 {
+			w.WriteString(" AND ")
+		}
+		if err := putQualified(w, "target", k, " = ", "source", k); err != nil {
+			return err
+		}
+	}
+	if len(updates) > 0 {
+		w.WriteString(" WHEN MATCHED THEN UPDATE SET ")
+		for i, u := range updates {
+			if i > 0 {
+				w.WriteString(", ")
+			}
+			if err := putQualified(w, "target", u, " = ", "source", u); err != nil {
+				return err
+			}
+		}
+	}
+	w.WriteString(" WHEN NOT MATCHED THEN INSERT (")
+	if err := putIdentList(w, cols); err != nil {
+		return err
+	}
+	w.WriteString(") VALUES (")
+	for i, c := range cols {
+		if i > 0 {
+			w.WriteString(", ")
+		}
+		w.WriteString("source.")
+		if err := writeIdent(w, c); err != nil {
+			return err
+		}
+	}
+	w.WriteString(");")
+	return nil
+}
+
+// putQualified は x.`a` op y.`b` を展開します.
+func putQualified(w Writer, x, a, op, y, b string) error {
+	w.WriteString(x)
+	w.WriteByte('.')
+	if err := writeIdent(w, a); err != nil {
+		return err
+	}
+	w.WriteString(op)
+	w.WriteString(y)
+	w.WriteByte('.')
+	return writeIdent(w, b)
+}
@@ -0,0 +1,72 @@
+package sqlb
+
+import (
+	"fmt"
+	"testing"
+
+	d "github.com/17e10/go-sqlb/dialect"
+	"github.com/17e10/go-sqlb/dialect/mysql"
+)
+
+func TestUpsert(t *testing.T) {
+	tests := []struct {
+		dialect d.Dialect
+		sqler   Sqler
+		want    string
+		err     string
+	}{
+		{nil, Upsert("person", "id").Struct(&olivia),
+			"INSERT INTO `person` (`id`, `family_name`, `given_name`, `age`) VALUES (1, 'Williams', 'Olivia', 54) " +
+				"ON DUPLICATE KEY UPDATE `family_name` = VALUES(`family_name`), `given_name` = VALUES(`given_name`), `age` = VALUES(`age`)", ""},
+		{nil, Upsert("counter", "name").Set(Kv{"name", "a"}, Kv{"n", 1}).Update(),
+			"INSERT INTO `counter` (`name`, `n`) VALUES ('a', 1) ON DUPLICATE KEY UPDATE `name` = `name`", ""},
+		{pgDialect{dialect()}, Upsert("person", "id").Structs(persons).Update("age"),
+			"INSERT INTO `person` (`id`, `family_name`, `given_name`, `age`) VALUES (1, 'Williams', 'Olivia', 54), (2, 'Loggins', 'Kenny', 75) " +
+				"ON CONFLICT (`id`) DO UPDATE SET `age` = EXCLUDED.`age`", ""},
+		{pgDialect{dialect()}, Upsert("counter", "name").Set(Kv{"name", "a"}).Update(),
+			"INSERT INTO `counter` (`name`) VALUES ('a') ON CONFLICT (`name`) DO NOTHING", ""},
+		{msDialect{dialect()}, Upsert("person", "id").Struct(&olivia).Update("given_name", "age"),
+			"MERGE INTO `person` AS target USING (VALUES (1, 'Williams', 'Olivia', 54)) AS source (`id`, `family_name`, `given_name`, `age`) " +
+				"ON target.`id` = source.`id` WHEN MATCHED THEN UPDATE SET target.`given_name` = source.`given_name`, target.`age` = source.`age` " +
+				"WHEN NOT MATCHED THEN INSERT (`id`, `family_name`, `given_name`, `age`) VALUES (source.`id`, source.`family_name`, source.`given_name`, source.`age`);", ""},
+		{nil, Upsert("person").Struct(&olivia), "", "upsert: keys: empty array or slice"},
+		{nil, Upsert("person", "pk").Struct(&olivia), "", "upsert: key pk: no such key"},
+		{nil, Upsert("person", "id").Struct(&olivia).Update("nop"), "", "upsert: update nop: no such key"},
+	}
+
+	old := d.GetDialect()
+	defer d.SetDialect(old)
+
+	for i, te := range tests {
+		var goterr string
+
+		if te.dialect != nil {
+			d.SetDialect(te.dialect)
+		} else {
+			d.SetDialect(old)
+		}
+		name := fmt.Sprintf("test Upsert #%d", i)
+		got, err := Stringify(te.sqler)
+		if err != nil {
+			goterr = err.Error()
+		}
+		if goterr != te.err {
+			t.Errorf("%s errored %q, want %q", name, goterr, te.err)
+		}
+		if got != te.want {
+			t.Errorf("%s returned %q, want %q", name, got, te.want)
+		}
+	}
+}
+
+func TestUpsertRowAlias(t *testing.T) {
+	old := d.GetDialect()
+	mysql.SetRowAlias(true)
+	defer d.SetDialect(old)
+
+	got, err := Stringify(Upsert("counter", "name").Set(Kv{"name", "a"}, Kv{"n", 1}))
+	want := "INSERT INTO `counter` (`name`, `n`) VALUES ('a', 1) AS new ON DUPLICATE KEY UPDATE `n` = new.`n`"
+	if err != nil || got != want {
+		t.Errorf("%s = %q, %v, want %q", "test Upsert row alias", got, err, want)
+	}
+}
@@ -0,0 +1,124 @@
+package sqlb
+
+import "fmt"
+
+// WithBuilder は共通テーブル式 (CTE) を Sqler の前に付ける WITH 句を組み立てます.
+//
+// WithBuilder は SelectBuilder と同様に不変です.
+//
+//	q := sqlb.With("adult", nil, sqlb.Select().From("person").Where(sqlb.T("age >= @", 20))).
+//		Prefix(sqlb.Select(sqlb.T("COUNT(*)")).From("adult"))
+type WithBuilder struct {
+	recursive bool
+	ctes      []cte
+}
+
+// cte は 1 つの共通テーブル式を表します.
+type cte struct {
+	name         string
+	columns      []string
+	query        Sqler
+	materialized int // 1: MATERIALIZED, -1: NOT MATERIALIZED
+}
+
+// With は name という共通テーブル式を持つ WithBuilder を作成します.
+//
+// columns を省略するときは nil を指定します.
+func With(name string, columns []string, query Sqler) *WithBuilder {
+	return (&WithBuilder{}).With(name, columns, query)
+}
+
+// With は共通テーブル式を追加します.
+func (b *WithBuilder) With(name string, columns []string, query Sqler) *WithBuilder {
+	c := *b
+	c.ctes = appendClone(c.ctes, cte{name: name, columns: columns, query: query})
+	return &c
+}
+
+// Recursive は WITH RECURSIVE にします.
+//
+// 再帰的な CTE に RECURSIVE キーワードを必要としない dialect では何も付けません.
+func (b *WithBuilder) Recursive() *WithBuilder {
+	c := *b
+	c.recursive = true
+	return &c
+}
+
+// Materialized は直前に追加した共通テーブル式に MATERIALIZED ヒントを付けます.
+//
+// ヒントをサポートしない dialect では無視します.
+func (b *WithBuilder) Materialized() *WithBuilder {
+	return b.materialize(1)
+}
+
+// NotMaterialized は直前に追加した共通テーブル式に NOT MATERIALIZED ヒントを付けます.
+//
+// ヒントをサポートしない dialect では無視します.
+func (b *WithBuilder) NotMaterialized() *WithBuilder {
+	return b.materialize(-1)
+}
+
+func (b *WithBuilder) materialize(m int) *WithBuilder {
+	c := *b
+	c.ctes = make([]cte, len(b.ctes))
+	copy(c.ctes, b.ctes)
+	if l := len(c.ctes); l > 0 {
+		c.ctes[l-1].materialized = m
+	}
+	return &c
+}
+
+// Prefix は WITH 句を sqler の前に付けた Sqler を返します.
+func (b *WithBuilder) Prefix(sqler Sqler) Sqler {
+	fn := func(w Writer) error {
+		if err := b.sql(w); err != nil {
+			return fmt.Errorf("with: %w", err)
+		}
+		w.WriteByte(' ')
+		return writeSqler(w, sqler)
+	}
+	return sqlerFunc(fn)
+}
+
+func (b *WithBuilder) sql(w Writer) error {
+	if len(b.ctes) == 0 {
+		return errEmptySlice
+	}
+
+	d := dialect()
+	w.WriteString("WITH ")
+	if kw := d.WithRecursive(); b.recursive && kw != "" {
+		w.WriteString(kw)
+		w.WriteByte(' ')
+	}
+	for i, c := range b.ctes {
+		if i > 0 {
+			w.WriteString(", ")
+		}
+		if err := writeIdent(w, c.name); err != nil {
+			return err
+		}
+		if c.columns != nil {
+			w.WriteString(" (")
+			if err := putIdentList(w, c.columns); err != nil {
+				return err
+			}
+			w.WriteByte(')')
+		}
+		w.WriteString(" AS ")
+		if d.Materialized() {
+			switch c.materialized {
+			case 1:
+				w.WriteString("MATERIALIZED ")
+			case -1:
+				w.WriteString("NOT MATERIALIZED ")
+			}
+		}
+		w.WriteByte('(')
+		if err := writeSqler(w, c.query); err != nil {
+			return err
+		}
+		w.WriteByte(')')
+	}
+	return nil
+}
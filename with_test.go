@@ -0,0 +1,75 @@
+package sqlb
+
+import (
+	"fmt"
+	"testing"
+
+	d "github.com/17e10/go-sqlb/dialect"
+)
+
+func TestWith(t *testing.T) {
+	adult := Select().From("person").Where(T("age >= @", 20))
+	count := Select(T("COUNT(*)")).From("adult")
+	tree := T("SELECT 1 UNION ALL SELECT n + 1 FROM t WHERE n < @", 10)
+
+	tests := []struct {
+		dialect d.Dialect
+		sqler   Sqler
+		want    string
+		err     string
+	}{
+		{nil, With("adult", nil, adult).Prefix(count),
+			"WITH `adult` AS (SELECT * FROM `person` WHERE age >= 20) SELECT COUNT(*) FROM `adult`", ""},
+		{nil, With("t", []string{"n"}, tree).Recursive().Prefix(T("SELECT n FROM t")),
+			"WITH RECURSIVE `t` (`n`) AS (SELECT 1 UNION ALL SELECT n + 1 FROM t WHERE n < 10) SELECT n FROM t", ""},
+		{nil, With("adult", nil, adult).Materialized().With("c", nil, count).Prefix(T("SELECT * FROM c")),
+			"WITH `adult` AS (SELECT * FROM `person` WHERE age >= 20), `c` AS (SELECT COUNT(*) FROM `adult`) SELECT * FROM c", ""},
+		{pgDialect{dialect()}, With("adult", nil, adult).Materialized().With("c", nil, count).NotMaterialized().Prefix(T("SELECT * FROM c")),
+			"WITH `adult` AS MATERIALIZED (SELECT * FROM `person` WHERE age >= 20), `c` AS NOT MATERIALIZED (SELECT COUNT(*) FROM `adult`) SELECT * FROM c", ""},
+		{msDialect{dialect()}, With("t", []string{"n"}, tree).Recursive().Prefix(T("SELECT n FROM t")),
+			"WITH `t` (`n`) AS (SELECT 1 UNION ALL SELECT n + 1 FROM t WHERE n < 10) SELECT n FROM t", ""},
+		{nil, With("a*", nil, adult).Prefix(count), "", "with: not allowed asterisk"},
+	}
+
+	old := d.GetDialect()
+	defer d.SetDialect(old)
+
+	for i, te := range tests {
+		var goterr string
+
+		if te.dialect != nil {
+			d.SetDialect(te.dialect)
+		} else {
+			d.SetDialect(old)
+		}
+		name := fmt.Sprintf("test With #%d", i)
+		got, err := Stringify(te.sqler)
+		if err != nil {
+			goterr = err.Error()
+		}
+		if goterr != te.err {
+			t.Errorf("%s errored %q, want %q", name, goterr, te.err)
+		}
+		if got != te.want {
+			t.Errorf("%s returned %q, want %q", name, got, te.want)
+		}
+	}
+}
+
+func TestWithMaterializeImmutable(t *testing.T) {
+	old := d.GetDialect()
+	defer d.SetDialect(old)
+	d.SetDialect(pgDialect{dialect()})
+
+	base := With("a", nil, T("SELECT 1"))
+	_ = base.Materialized()
+	_ = base.NotMaterialized()
+
+	got, err := Stringify(base.Prefix(T("SELECT * FROM a")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "WITH `a` AS (SELECT 1) SELECT * FROM a"; got != want {
+		t.Errorf("base returned %q, want %q", got, want)
+	}
+}
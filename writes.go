@@ -21,9 +21,16 @@ func writeIdent(w Writer, s string) error {
 //
 // v が受け取れるのは bool, int, float, string などの基底型や []byte, time.Time です.
 // v が driver.Valuer インターフェイスを実装していればそれを利用します.
+// w が StringifyRedacted の Writer ならば値を伏せたマーカーを書き込みます.
 func writeValue(w Writer, v any) (err error) {
 	d := dialect()
 
+	// Sensitive を外す
+	s, sensitive := v.(SensitiveValue)
+	if sensitive {
+		v = s.v
+	}
+
 	// Valuer を反映する
 	if valuer, ok := v.(driver.Valuer); ok {
 		v, err = valuer.Value()
@@ -32,6 +39,10 @@ func writeValue(w Writer, v any) (err error) {
 		}
 	}
 
+	if v != nil && redacts(w, sensitive) {
+		return writeRedacted(w, v)
+	}
+
 	switch x := v.(type) {
 	case nil:
 		err = d.WriteNull(w)